@@ -0,0 +1,57 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package cmds
+
+import (
+	"qtcli/runner"
+	"qtcli/util"
+
+	"github.com/spf13/cobra"
+)
+
+type answerFlags struct {
+	sets []string
+	file string
+	yes  bool
+}
+
+func (f *answerFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(
+		&f.sets, "set", []string{},
+		util.Msg("Answer a prompt, e.g. --set minimumQtVersion=6.5 "+
+			"(can be repeated)"))
+
+	cmd.Flags().StringVar(
+		&f.file, "answers", "",
+		util.Msg("Read prompt answers from a YAML or JSON file"))
+
+	cmd.Flags().BoolVarP(
+		&f.yes, "yes", "y", false,
+		util.Msg("Accept default values for everything not answered, "+
+			"and the first default preset if none is given"))
+}
+
+// load merges answers from the file and --set, the latter wins
+func (f *answerFlags) load() (runner.Answers, error) {
+	values := util.StringAnyMap{}
+
+	if len(f.file) != 0 {
+		fromFile, err := runner.ReadAnswersFile(f.file)
+		if err != nil {
+			return runner.Answers{}, err
+		}
+
+		values = fromFile
+	}
+
+	fromArgs, err := runner.ParseAnswerArgs(f.sets)
+	if err != nil {
+		return runner.Answers{}, err
+	}
+
+	return runner.Answers{
+		Values:         util.Merge(values, fromArgs),
+		AcceptDefaults: f.yes,
+	}, nil
+}
@@ -0,0 +1,89 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package cmds
+
+import (
+	"fmt"
+	"os"
+	"qtcli/common"
+	"qtcli/generator"
+	"qtcli/runner"
+	"qtcli/server/handlers"
+	"qtcli/util"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type generateFlags struct {
+	onConflict string
+	dryRun     bool
+	noHooks    bool
+}
+
+func (f *generateFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&f.onConflict, "on-conflict", string(generator.ConflictFail),
+		util.Msg("What to do with existing files: "+
+			"fail, skip, overwrite, backup or prompt"))
+
+	cmd.Flags().BoolVar(
+		&f.dryRun, "dry-run", false,
+		util.Msg("Show what would be written, without writing anything"))
+
+	cmd.Flags().BoolVar(
+		&f.noHooks, "no-hooks", false,
+		util.Msg("Do not run the post-generation hooks of the template"))
+}
+
+func (f *generateFlags) conflictPolicy() (generator.ConflictPolicy, error) {
+	return generator.ParseConflictPolicy(f.onConflict)
+}
+
+// apply configures the generator according to the flags
+func (f *generateFlags) apply(g *generator.Generator) (*generator.Generator, error) {
+	policy, err := f.conflictPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	return g.ConflictPolicy(policy).
+		ConflictResolver(runner.RunConflictPrompt).
+		DryRun(f.dryRun).
+		NoHooks(f.noHooks), nil
+}
+
+// report prints the preview of a dry run, or the list of files written
+// when verbose. With a structured output, it prints the result as a
+// NewItemResponse instead. It fails if a post-generation hook has failed.
+func (f *generateFlags) report(
+	data *generator.ResultData, preset common.Preset) error {
+	hook, failed := data.GetFailedHook()
+
+	if isStructuredOutput() {
+		err := printDocument(handlers.NewItemResponseFrom(data,
+			preset.GetTypeName(), data.GetWorkingDir(), f.dryRun))
+		if err != nil {
+			return err
+		}
+	} else if f.dryRun {
+		data.PrintPreview(os.Stdout)
+	} else {
+		if verbose {
+			data.Print(logrus.New().Writer())
+		}
+
+		if failed {
+			os.Stderr.WriteString(hook.Output)
+		}
+	}
+
+	if failed {
+		return withExitCode(exitHookFailed, fmt.Errorf(
+			util.Msg("files were generated, but hook '%s' failed: %s"),
+			hook.Name, hook.Error))
+	}
+
+	return nil
+}
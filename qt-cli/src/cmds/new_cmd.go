@@ -12,11 +12,12 @@ import (
 	"qtcli/runner"
 	"qtcli/util"
 
-	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
 var newPresetName string
+var newAnswers answerFlags
+var newGenerateFlags generateFlags
 
 var newCmd = &cobra.Command{
 	Use:   "new <project-name>",
@@ -25,40 +26,56 @@ var newCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		name := args[0]
 		cwd, _ := os.Getwd()
+		policy, err := newGenerateFlags.conflictPolicy()
+		if err != nil {
+			return err
+		}
+
 		out := generator.Validate(generator.ValidatorIn{
-			Name:       name,
-			WorkingDir: filepath.ToSlash(cwd),
-			TypeId:     common.TargetTypeProject,
+			Name:           name,
+			WorkingDir:     filepath.ToSlash(cwd),
+			TypeId:         common.TargetTypeProject,
+			ConflictPolicy: policy,
+			DryRun:         newGenerateFlags.dryRun,
 		})
 
 		if out.HasError() {
 			return fmt.Errorf(
-				util.Msg("Cannot generate the project\n%s"), out)
+				util.Msg("Cannot generate the project\n%w"), common.Error{
+					Message: common.InputHasIssues,
+					Details: out,
+				})
+		}
+
+		answers, err := newAnswers.load()
+		if err != nil {
+			return err
 		}
 
 		const targetType = common.TargetTypeProject
-		preset, err := runner.FindPresetOrRunSelector(targetType, newPresetName)
+		preset, err := runner.FindPresetWithAnswers(
+			targetType, newPresetName, answers)
 		if err != nil {
 			return fmt.Errorf(
 				util.Msg("failed to select a preset: '%w'"), err)
 		}
 
-		result := generator.NewGenerator(name).
+		g, err := newGenerateFlags.apply(generator.NewGenerator(name).
 			Env(runner.GeneratorEnv).
-			Preset(preset).
-			Render()
+			Preset(preset))
+		if err != nil {
+			return err
+		}
+
+		result := g.Render()
 
 		if !result.Success {
 			return fmt.Errorf(
-				util.Msg("failed to generate a project\n%s"),
+				util.Msg("failed to generate a project\n%w"),
 				result.Error)
 		}
 
-		if verbose {
-			result.Data.Print(logrus.New().Writer())
-		}
-
-		return nil
+		return newGenerateFlags.report(&result.Data, preset)
 	},
 }
 
@@ -67,5 +84,7 @@ func init() {
 		&newPresetName, "preset", "",
 		util.Msg("Specify a preset to use"))
 
+	newAnswers.register(newCmd)
+	newGenerateFlags.register(newCmd)
 	rootCmd.AddCommand(newCmd)
 }
@@ -16,6 +16,9 @@ import (
 )
 
 var newFilePresetName string
+var newFileAnswers answerFlags
+var newFileGenerateFlags generateFlags
+var newFileNoCMake bool
 
 var newFileCmd = &cobra.Command{
 	Use:   "new-file [file-name]",
@@ -25,8 +28,17 @@ var newFileCmd = &cobra.Command{
 		var selected common.Preset
 		const targetType = common.TargetTypeFile
 
+		answers, err := newFileAnswers.load()
+		if err != nil {
+			return err
+		}
+
 		if len(args) == 0 {
-			name = runner.RunFileNamePrompt()
+			name, err = runner.RunFileNamePrompt()
+			if err != nil {
+				return err
+			}
+
 			if len(name) == 0 {
 				return nil
 			}
@@ -35,7 +47,7 @@ var newFileCmd = &cobra.Command{
 		}
 
 		if ext := path.Ext(name); len(ext) != 0 {
-			userPreset, err := runner.RunFilePromptByExt(ext)
+			userPreset, err := runner.RunFilePromptByExt(ext, answers)
 			if err != nil {
 				return err
 			}
@@ -48,28 +60,31 @@ var newFileCmd = &cobra.Command{
 			name = strings.TrimSuffix(name, ext)
 			selected = userPreset
 		} else {
-			var err error
-			selected, err = runner.FindPresetOrRunSelector(
-				targetType, newFilePresetName)
+			selected, err = runner.FindPresetWithAnswers(
+				targetType, newFilePresetName, answers)
 			if err != nil {
 				return fmt.Errorf(
 					util.Msg("failed to find or select a preset: '%w'"), err)
 			}
 		}
 
-		result := generator.NewGenerator(name).
+		g, err := newFileGenerateFlags.apply(generator.NewGenerator(name).
 			Env(runner.GeneratorEnv).
 			Preset(selected).
-			Render()
+			NoCMake(newFileNoCMake))
+		if err != nil {
+			return err
+		}
+
+		result := g.Render()
 
 		if !result.Success {
 			return fmt.Errorf(
 				util.Msg("failed to generate a file: '%w'"),
-				result.Error.Message)
-
+				result.Error)
 		}
 
-		return nil
+		return newFileGenerateFlags.report(&result.Data, selected)
 	},
 }
 
@@ -78,5 +93,11 @@ func init() {
 		&newFilePresetName, "preset", "",
 		util.Msg("Specify a preset to use"))
 
+	newFileCmd.Flags().BoolVar(
+		&newFileNoCMake, "no-cmake", false,
+		util.Msg("Do not add the new files to the nearest CMakeLists.txt"))
+
+	newFileAnswers.register(newFileCmd)
+	newFileGenerateFlags.register(newFileCmd)
 	rootCmd.AddCommand(newFileCmd)
 }
@@ -0,0 +1,179 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package cmds
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"qtcli/common"
+	"qtcli/util"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+type outputFormat string
+
+const (
+	outputText outputFormat = "text"
+	outputJson outputFormat = "json"
+	outputYaml outputFormat = "yaml"
+)
+
+var outputFormats = []outputFormat{outputText, outputJson, outputYaml}
+
+// exit codes of qtcli, see 'Scripting' in README.md
+const (
+	exitOk           = 0
+	exitFailure      = 1
+	exitUsage        = 2
+	exitInvalidInput = 3
+	exitHookFailed   = 4
+)
+
+var outputFlag = string(outputText)
+
+func parseOutputFormat(s string) (outputFormat, error) {
+	format := outputFormat(s)
+	if !slices.Contains(outputFormats, format) {
+		return outputText, fmt.Errorf(util.Msg(
+			"invalid output format, given = '%v', expected = text, json or yaml"),
+			s)
+	}
+
+	return format, nil
+}
+
+func isStructuredOutput() bool {
+	return outputFormat(outputFlag) != outputText
+}
+
+// printResult writes a command result to stdout: as a document in the
+// selected format, or by calling printText
+func printResult(doc any, printText func(w io.Writer)) error {
+	if !isStructuredOutput() {
+		printText(os.Stdout)
+		return nil
+	}
+
+	return printDocument(doc)
+}
+
+// printStatus writes doc with a structured output only, for commands
+// which print nothing on success otherwise
+func printStatus(doc any) error {
+	if !isStructuredOutput() {
+		return nil
+	}
+
+	return printDocument(doc)
+}
+
+// printDocument writes doc to stdout in the selected structured format
+func printDocument(doc any) error {
+	return writeDocument(os.Stdout, outputFormat(outputFlag), doc)
+}
+
+// writeDocument encodes doc in the given format. Documents are first
+// encoded as JSON, so that both formats use the field names of the
+// json tags.
+func writeDocument(w io.Writer, format outputFormat, doc any) error {
+	raw, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if format != outputYaml {
+		_, err = fmt.Fprintln(w, string(raw))
+		return err
+	}
+
+	// JSON is YAML, and decoding to a node keeps the order of fields
+	node := yaml.Node{}
+	if err := yaml.Unmarshal(raw, &node); err != nil {
+		return err
+	}
+
+	resetYamlStyle(&node)
+	raw, err = yaml.Marshal(&node)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(raw)
+	return err
+}
+
+// resetYamlStyle turns the flow style of decoded JSON to the block style
+func resetYamlStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetYamlStyle(child)
+	}
+}
+
+// exitError gives an error a specific exit code
+type exitError struct {
+	code int
+	err  error
+}
+
+func withExitCode(code int, err error) error {
+	return exitError{code: code, err: err}
+}
+
+func (e exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e exitError) Unwrap() error {
+	return e.err
+}
+
+func exitCodeOf(err error) int {
+	if err == nil {
+		return exitOk
+	}
+
+	var coded exitError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+
+	var details common.Error
+	if errors.As(err, &details) && details.Details.HasError() {
+		return exitInvalidInput
+	}
+
+	return exitFailure
+}
+
+// errorDocument describes an error as a common.Error, keeping the
+// details of the underlying common.Error if any
+func errorDocument(err error) common.Error {
+	doc := common.Error{Message: err.Error()}
+
+	var details common.Error
+	if errors.As(err, &details) {
+		doc.Details = details.Details
+	}
+
+	return doc
+}
+
+// printError writes an error to stderr, as text or as a document
+func printError(err error) {
+	format, _ := parseOutputFormat(outputFlag)
+	if format == outputText {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return
+	}
+
+	if writeDocument(os.Stderr, format, errorDocument(err)) != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+	}
+}
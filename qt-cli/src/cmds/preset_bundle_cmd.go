@@ -0,0 +1,144 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package cmds
+
+import (
+	"fmt"
+	"io"
+	"qtcli/common"
+	"qtcli/runner"
+	"qtcli/server/handlers"
+	"qtcli/util"
+
+	"github.com/spf13/cobra"
+)
+
+var presetExportFile string
+var presetBundleScope string
+var presetImportRename bool
+var presetImportOverwrite bool
+
+var presetExportCmd = &cobra.Command{
+	Use:   "export [preset-name...]",
+	Short: util.Msg("Write presets to a bundle file"),
+	Long: util.Msg("Write presets to a bundle file.\n\n" +
+		"Without names, all the presets of the scope are exported. " +
+		"The bundle has the format of a preset file, and is written " +
+		"to stdout unless a file is given."),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := scopedPresetFile(presetBundleScope)
+		if err != nil {
+			return err
+		}
+
+		bundle, err := f.Export(args)
+		if err != nil {
+			return err
+		}
+
+		if len(presetExportFile) == 0 {
+			return printResult(bundle, func(w io.Writer) {
+				raw, _ := bundle.ToYaml()
+				w.Write(raw)
+			})
+		}
+
+		raw, err := bundle.ToYaml()
+		if err != nil {
+			return err
+		}
+
+		if _, err := util.WriteAll(raw, presetExportFile); err != nil {
+			return err
+		}
+
+		res := presetExportResponse{File: presetExportFile, Presets: []string{}}
+		for _, item := range bundle.Items {
+			res.Presets = append(res.Presets, item.Name)
+		}
+
+		return printResult(res, res.print)
+	},
+}
+
+var presetImportCmd = &cobra.Command{
+	Use:   "import <bundle-file>",
+	Short: util.Msg("Add the presets of a bundle file"),
+	Long: util.Msg("Add the presets of a bundle file.\n\n" +
+		"Each preset is checked against its template first, and nothing " +
+		"is imported if one of them is invalid. By default, the import " +
+		"fails if a preset name is already taken."),
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		policy := common.ImportFail
+		if presetImportRename {
+			policy = common.ImportRename
+		} else if presetImportOverwrite {
+			policy = common.ImportOverwrite
+		}
+
+		f, err := scopedPresetFile(presetBundleScope)
+		if err != nil {
+			return err
+		}
+
+		bundle, err := common.ReadPresetBundle(args[0])
+		if err != nil {
+			return err
+		}
+
+		var imported []common.ImportedPreset
+		err = f.Update(func(f *common.UserPresetFile) error {
+			imported, err = f.Import(runner.GeneratorEnv.FS, bundle, policy)
+			return err
+		})
+
+		if err != nil {
+			return err
+		}
+
+		res := handlers.NewPresetImportResponse(imported)
+		return printResult(res, func(w io.Writer) {
+			for _, p := range res.Presets {
+				if len(p.From) != 0 {
+					fmt.Fprintf(w, "%-12s%s -> %s\n", p.State, p.From, p.Name)
+				} else {
+					fmt.Fprintf(w, "%-12s%s\n", p.State, p.Name)
+				}
+			}
+		})
+	},
+}
+
+type presetExportResponse struct {
+	File    string   `json:"file"`
+	Presets []string `json:"presets"`
+}
+
+func (r presetExportResponse) print(w io.Writer) {
+	fmt.Fprintf(w, util.Msg("Exported %d preset(s) to %s\n"),
+		len(r.Presets), r.File)
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{presetExportCmd, presetImportCmd} {
+		cmd.Flags().StringVar(&presetBundleScope, "scope",
+			string(common.PresetScopeUser),
+			util.Msg("Scope of the presets: project or user"))
+	}
+
+	presetExportCmd.Flags().StringVarP(&presetExportFile, "file", "f", "",
+		util.Msg("File to write the bundle to"))
+
+	presetImportCmd.Flags().BoolVar(&presetImportRename,
+		"rename-on-conflict", false,
+		util.Msg("Import a preset whose name is taken under a new name"))
+	presetImportCmd.Flags().BoolVar(&presetImportOverwrite,
+		"overwrite", false,
+		util.Msg("Replace the preset having the same name"))
+	presetImportCmd.MarkFlagsMutuallyExclusive("rename-on-conflict", "overwrite")
+
+	presetCmd.AddCommand(presetExportCmd)
+	presetCmd.AddCommand(presetImportCmd)
+}
@@ -9,6 +9,7 @@ import (
 	"qtcli/common"
 	"qtcli/prompt/comps"
 	"qtcli/runner"
+	"qtcli/server/handlers"
 	"qtcli/util"
 
 	"github.com/spf13/cobra"
@@ -26,13 +27,23 @@ var presetListCmd = &cobra.Command{
 	Use:   "ls",
 	Short: util.Msg("List the names of all presets"),
 	Args:  cobra.ExactArgs(0),
-	Run: func(cmd *cobra.Command, args []string) {
-		items := runner.Presets.User.GetAll()
-		items = append(items, runner.Presets.Default.GetAll()...)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		items := runner.Presets.Any.GetAll()
+
+		if !isStructuredOutput() {
+			for _, item := range items {
+				fmt.Printf("%-8s %s\n", item.GetScope(), item.GetDescription())
+			}
+
+			return nil
+		}
 
-		for _, item := range items {
-			fmt.Println(item.GetDescription())
+		res, err := handlers.NewPresetsResponse(items)
+		if err != nil {
+			return err
 		}
+
+		return printDocument(res)
 	},
 }
 
@@ -47,55 +58,78 @@ var presetCatCmd = &cobra.Command{
 			return err
 		}
 
-		fmt.Println("id:", item.GetUniqueId())
-		fmt.Println(item.ToYaml())
-		return nil
+		if !isStructuredOutput() {
+			fmt.Println("id:", item.GetUniqueId())
+			fmt.Println(item.ToYaml())
+			return nil
+		}
+
+		res, err := handlers.NewPresetDetailResponse(item)
+		if err != nil {
+			return err
+		}
+
+		return printDocument(res)
 	},
 }
 
+var presetScope string
+
 var presetMoveCmd = &cobra.Command{
 	Use:   "mv <from:preset-name> <to:new-preset-name>",
-	Short: util.Msg("Rename a user preset"),
+	Short: util.Msg("Rename a user or project preset"),
 	Args:  cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if !userPresets().Contains(args[0]) {
-			return errors.New(util.Msg("preset not found"))
-		}
-
-		err := userPresets().Rename(args[0], args[1])
+		f, err := presetFileOf(args[0], presetScope)
 		if err != nil {
 			return err
 		}
 
-		if err := userPresets().Save(); err != nil {
+		err = f.Update(func(f *common.UserPresetFile) error {
+			return f.Rename(args[0], args[1])
+		})
+
+		if err != nil {
 			return err
 		}
 
-		return nil
+		return printStatus(handlers.StatusAndIdResponse{
+			Status: common.ServerStatusUpdated,
+			Id:     util.CreatePresetUniqueId(args[1]),
+		})
 	},
 }
 
 var presetRemoveCmd = &cobra.Command{
 	Use:   "rm <preset-name>",
-	Short: util.Msg("Remove a user preset"),
+	Short: util.Msg("Remove a user or project preset"),
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if !userPresets().Contains(args[0]) {
-			return errors.New(util.Msg("preset not found"))
+		f, err := presetFileOf(args[0], presetScope)
+		if err != nil {
+			return err
 		}
 
 		msg := util.Msg("Are you sure you want to remove this preset?")
-		if getConfirm(msg) {
-			if err := userPresets().Remove(args[0]); err != nil {
-				return err
-			}
+		if !getConfirm(msg) {
+			return printStatus(handlers.StatusResponse{
+				Status: common.StatusCancelled,
+			})
+		}
 
-			if err := userPresets().Save(); err != nil {
-				return err
-			}
+		err = f.Update(func(f *common.UserPresetFile) error {
+			return f.Remove(args[0])
+		})
+
+		if err != nil {
+			return err
 		}
 
-		return nil
+		return printStatus(handlers.PresetDeleteResponse{
+			Name:     args[0],
+			PresetId: util.CreatePresetUniqueId(args[0]),
+			Status:   common.ServerPresetDeleted,
+		})
 	},
 }
 
@@ -106,18 +140,30 @@ var presetClearCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		count := userPresets().GetCount()
 		if count == 0 {
-			return nil
+			return printStatus(handlers.StatusResponse{
+				Status: common.StatusNothingToDo,
+			})
 		}
 
 		msg := util.Msg("Are you sure you want to remove all presets?")
-		if getConfirm(msg) {
-			userPresets().RemoveAll()
-			if err := userPresets().Save(); err != nil {
-				return err
-			}
+		if !getConfirm(msg) {
+			return printStatus(handlers.StatusResponse{
+				Status: common.StatusCancelled,
+			})
+		}
+
+		err := userPresets().Update(func(f *common.UserPresetFile) error {
+			f.RemoveAll()
+			return nil
+		})
+
+		if err != nil {
+			return err
 		}
 
-		return nil
+		return printStatus(handlers.StatusResponse{
+			Status: common.StatusPresetsCleared,
+		})
 	},
 }
 
@@ -135,7 +181,66 @@ func userPresets() *common.UserPresetFile {
 	return runner.Presets.User.GetFile()
 }
 
+// presetFileOf returns the file of the given scope having the preset.
+// Without a scope, the project presets are looked up first, the same
+// way presets are found when generating.
+func presetFileOf(name, scope string) (*common.UserPresetFile, error) {
+	files := []*common.UserPresetFile{
+		runner.Presets.Project.GetFile(),
+		runner.Presets.User.GetFile(),
+	}
+
+	if len(scope) != 0 {
+		f, err := scopedPresetFile(scope)
+		if err != nil {
+			return nil, err
+		}
+
+		files = []*common.UserPresetFile{f}
+	}
+
+	for _, f := range files {
+		if f.Contains(name) {
+			return f, nil
+		}
+	}
+
+	return nil, errors.New(util.Msg("preset not found"))
+}
+
+// scopedPresetFile returns the preset file of the given scope
+func scopedPresetFile(scope string) (*common.UserPresetFile, error) {
+	switch common.PresetScope(scope) {
+	case common.PresetScopeProject:
+		f := runner.Presets.Project.GetFile()
+		if len(f.GetFilePath()) == 0 {
+			return nil, fmt.Errorf(util.Msg(
+				"no project presets, neither '%v' nor '%v' was found"),
+				common.ProjectPresetFileName, common.ProjectPresetDirFileName)
+		}
+
+		return f, nil
+
+	case common.PresetScopeUser:
+		return runner.Presets.User.GetFile(), nil
+
+	case common.PresetScopeDefault:
+		return nil, withExitCode(exitUsage, errors.New(
+			util.Msg("default presets cannot be changed")))
+	}
+
+	return nil, withExitCode(exitUsage, fmt.Errorf(
+		util.Msg("invalid scope, expected project or user, given = '%v'"),
+		scope))
+}
+
 func init() {
+	for _, cmd := range []*cobra.Command{presetMoveCmd, presetRemoveCmd} {
+		cmd.Flags().StringVar(&presetScope, "scope", "",
+			util.Msg("Scope of the preset: project or user "+
+				"(default: the first one having it)"))
+	}
+
 	presetCmd.AddCommand(presetListCmd)
 	presetCmd.AddCommand(presetCatCmd)
 	presetCmd.AddCommand(presetMoveCmd)
@@ -5,6 +5,7 @@ package cmds
 
 import (
 	"os"
+	"qtcli/runner"
 	"qtcli/util"
 
 	"github.com/sirupsen/logrus"
@@ -12,11 +13,17 @@ import (
 )
 
 var verbose = false
+var plainPrompts = false
+var templateDirs []string
 
 var rootCmd = &cobra.Command{
 	Use:   "qtcli",
 	Short: util.Msg("A CLI for creating Qt project and files"),
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := parseOutputFormat(outputFlag); err != nil {
+			return withExitCode(exitUsage, err)
+		}
+
 		if verbose {
 			logrus.SetLevel(logrus.DebugLevel)
 		}
@@ -24,6 +31,12 @@ var rootCmd = &cobra.Command{
 		logrus.SetFormatter(&logrus.TextFormatter{
 			ForceColors: true,
 		})
+
+		// presets are checked against the given template directories
+		runner.UseTemplateDirs(templateDirs)
+		runner.LoadPresets()
+		runner.UsePlainPrompts(plainPrompts)
+		return nil
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		cmd.Help()
@@ -31,18 +44,57 @@ var rootCmd = &cobra.Command{
 }
 
 func Execute() {
+	markUsageErrors(rootCmd)
+
 	err := rootCmd.Execute()
 	if err != nil {
-		os.Exit(1)
+		printError(err)
+		os.Exit(exitCodeOf(err))
+	}
+}
+
+// markUsageErrors gives the exit code of usage errors to errors found
+// while checking the arguments of commands
+func markUsageErrors(cmd *cobra.Command) {
+	if check := cmd.Args; check != nil {
+		cmd.Args = func(cmd *cobra.Command, args []string) error {
+			if err := check(cmd, args); err != nil {
+				return withExitCode(exitUsage, err)
+			}
+
+			return nil
+		}
+	}
+
+	for _, child := range cmd.Commands() {
+		markUsageErrors(child)
 	}
 }
 
 func SetVersion(v string) {
 	rootCmd.Version = v
+	runner.GeneratorEnv.Version = v
 }
 
 func init() {
 	rootCmd.SilenceUsage = true
+	rootCmd.SilenceErrors = true
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return withExitCode(exitUsage, err)
+	})
 	rootCmd.PersistentFlags().BoolVarP(
 		&verbose, "verbose", "v", false, util.Msg("Enable verbose output"))
+
+	rootCmd.PersistentFlags().StringVar(
+		&outputFlag, "output", string(outputText),
+		util.Msg("Output format of results and errors: text, json or yaml"))
+
+	rootCmd.PersistentFlags().BoolVar(
+		&plainPrompts, "plain", false,
+		util.Msg("Ask questions line by line, without the terminal UI"))
+
+	rootCmd.PersistentFlags().StringArrayVar(
+		&templateDirs, "templates-dir", []string{},
+		util.Msg("Add a template directory searched before the others "+
+			"(can be repeated)"))
 }
@@ -4,44 +4,81 @@
 package cmds
 
 import (
+	"errors"
 	"qtcli/server"
 	"qtcli/util"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
 
 var useTcp bool
 var tcpPort string
+var listenAddr string
+var instanceName string
+var idleTimeout int
+var parentPid int
 
 var serverCmd = &cobra.Command{
 	Use:   "server <start|stop>",
 	Short: util.Msg("Start or stop a rest server"),
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		control := strings.ToLower(strings.TrimSpace(args[0]))
 
+		if err := server.ValidateInstanceName(instanceName); err != nil {
+			return withExitCode(exitUsage, err)
+		}
+
+		if idleTimeout < 0 || parentPid < 0 {
+			return withExitCode(exitUsage, errors.New(
+				util.Msg("--idle-timeout and --parent-pid cannot be negative")))
+		}
+
 		if control == "start" {
-			server.Start(server.Options{
-				UseTcp:  useTcp,
-				TcpPort: tcpPort,
+			return server.Start(server.Options{
+				UseTcp:      useTcp,
+				TcpPort:     tcpPort,
+				ListenAddr:  listenAddr,
+				Name:        instanceName,
+				IdleTimeout: time.Duration(idleTimeout) * time.Minute,
+				ParentPid:   parentPid,
 			})
 		} else if control == "stop" {
-			server.Stop()
+			server.Stop(instanceName)
 		} else {
 			cmd.Help()
 		}
+
+		return nil
 	},
 }
 
 func init() {
 	serverCmd.Flags().BoolVar(
 		&useTcp, "tcp", false,
-		util.Msg("Use TCP instead of local IPC"))
+		util.Msg("Use TCP on the loopback interface instead of local IPC"))
 
 	serverCmd.Flags().StringVar(
 		&tcpPort, "port", "8080",
 		util.Msg("Specify TCP port (effective only when --tcp is set)"))
 
+	serverCmd.Flags().StringVar(
+		&listenAddr, "listen", "",
+		util.Msg("TCP address to listen on, like 0.0.0.0:8080 (implies --tcp)"))
+
+	serverCmd.Flags().StringVar(
+		&instanceName, "name", "",
+		util.Msg("Name of the server instance, to run several servers side by side"))
+
+	serverCmd.Flags().IntVar(
+		&idleTimeout, "idle-timeout", 0,
+		util.Msg("Stop the server after this many minutes without requests (0: never)"))
+
+	serverCmd.Flags().IntVar(
+		&parentPid, "parent-pid", 0,
+		util.Msg("Stop the server when the process with this pid exits"))
+
 	rootCmd.AddCommand(serverCmd)
 }
@@ -0,0 +1,90 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package cmds
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"qtcli/generator"
+	"qtcli/util"
+
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status [dir]",
+	Short: util.Msg("Show the generated files changed since generation"),
+	Long: util.Msg("Show the generated files changed since generation.\n\n" +
+		"The project is found from the given directory, or the current " +
+		"one, by looking for the '.qtcli/manifest.yml' file written when " +
+		"the project was created."),
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, _ := os.Getwd()
+		if len(args) != 0 {
+			dir = args[0]
+		}
+
+		dir, err := filepath.Abs(dir)
+		if err != nil {
+			return err
+		}
+
+		projectDir, found := generator.FindManifest(filepath.ToSlash(dir))
+		if !found {
+			return fmt.Errorf(util.Msg(
+				"not a generated project, no manifest found, dir = '%v'"), dir)
+		}
+
+		manifest, err := generator.ReadManifest(projectDir)
+		if err != nil {
+			return err
+		}
+
+		files, err := manifest.Status(projectDir)
+		if err != nil {
+			return err
+		}
+
+		res := statusResponse{
+			ProjectDir: projectDir,
+			Manifest:   manifest,
+			Files:      files,
+		}
+
+		return printResult(res, res.print)
+	},
+}
+
+type statusResponse struct {
+	ProjectDir string                         `json:"projectDir"`
+	Manifest   *generator.Manifest            `json:"manifest"`
+	Files      []generator.ManifestFileStatus `json:"files"`
+}
+
+func (r statusResponse) print(w io.Writer) {
+	fmt.Fprintf(w, util.Msg("Project %s\n"), r.ProjectDir)
+	fmt.Fprintf(w, util.Msg("Generated from %s by qtcli %s\n\n"),
+		r.Manifest.Preset, r.Manifest.QtcliVersion)
+
+	changed := 0
+	for _, file := range r.Files {
+		if file.State == generator.ManifestFileUnchanged {
+			continue
+		}
+
+		fmt.Fprintf(w, "  %-10s%s\n", file.State, file.Path)
+		changed++
+	}
+
+	if changed == 0 {
+		fmt.Fprintln(w, util.Msg("No generated file was changed"))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+}
@@ -0,0 +1,328 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package cmds
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"qtcli/common"
+	"qtcli/generator"
+	"qtcli/golden"
+	"qtcli/runner"
+	"qtcli/util"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: util.Msg("Develop and check templates"),
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var templateLintCmd = &cobra.Command{
+	Use:   "lint <dir>",
+	Short: util.Msg("Check templates for mistakes without rendering them"),
+	Long: util.Msg("Check templates for mistakes without rendering them.\n\n" +
+		"<dir> is either a template directory, a directory containing " +
+		"template directories, or the name of a known template, " +
+		"e.g. @projects/cpp/console."),
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target, err := newTemplateTarget(args[0])
+		if err != nil {
+			return err
+		}
+
+		res := templateLintResponse{
+			Templates: len(target.dirs),
+			Issues:    common.Issues{},
+		}
+
+		for _, dir := range target.dirs {
+			for _, issue := range generator.Lint(target.fs, dir) {
+				issue.Field = target.display(issue.Field)
+				res.Issues = append(res.Issues, issue)
+			}
+		}
+
+		err = printResult(res, res.print)
+		if err != nil {
+			return err
+		}
+
+		if res.Issues.HasError() {
+			return errors.New(util.Msg("templates have errors"))
+		}
+
+		return nil
+	},
+}
+
+var templateTestCasesDir string
+var templateTestUpdate bool
+
+var templateTestCmd = &cobra.Command{
+	Use:   "test <dir>",
+	Short: util.Msg("Compare the output of templates with expected files"),
+	Long: util.Msg("Compare the output of templates with expected files.\n\n" +
+		"Test cases are read from the 'testdata' directory of each " +
+		"template directory, or from --cases. Each case is a directory " +
+		"with a 'case.yml' file, giving the 'name' of the item and the " +
+		"'answers', and an 'expected' directory with the files the " +
+		"template should generate."),
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target, err := newTemplateTarget(args[0])
+		if err != nil {
+			return err
+		}
+
+		if len(templateTestCasesDir) != 0 && len(target.dirs) != 1 {
+			return errors.New(
+				util.Msg("--cases needs a single template directory"))
+		}
+
+		env := &generator.Env{
+			FS:               target.fs,
+			FileTypesBaseDir: runner.GeneratorEnv.FileTypesBaseDir,
+			TemplateFileName: runner.GeneratorEnv.TemplateFileName,
+		}
+
+		res := templateTestResponse{Cases: []templateTestCase{}}
+
+		for _, dir := range target.dirs {
+			casesDir := templateTestCasesDir
+			if len(casesDir) == 0 {
+				if len(target.onDisk) == 0 {
+					return errors.New(util.Msg(
+						"--cases is needed for templates which are not on disk"))
+				}
+
+				casesDir = target.display(path.Join(dir, golden.TestDataDirName))
+			}
+
+			if !util.DirExists(casesDir) {
+				res.Cases = append(res.Cases, templateTestCase{
+					Name:   target.display(dir),
+					Status: templateTestNoCases,
+				})
+				continue
+			}
+
+			cases, err := golden.LoadCases(casesDir)
+			if err != nil {
+				return err
+			}
+
+			for _, c := range cases {
+				item := templateTestCase{
+					Name:   filepath.Join(target.display(dir), c.Id),
+					Status: templateTestOk,
+				}
+
+				mismatches, err := golden.Check(
+					env, dir, c, templateTestUpdate)
+
+				switch {
+				case err != nil:
+					item.Status = templateTestFailed
+					item.Error = err.Error()
+
+				case len(mismatches) != 0:
+					item.Status = templateTestFailed
+					item.Mismatches = mismatches
+
+				case templateTestUpdate:
+					item.Status = templateTestUpdated
+				}
+
+				res.Cases = append(res.Cases, item)
+			}
+		}
+
+		if err := printResult(res, res.print); err != nil {
+			return err
+		}
+
+		if failed := res.countFailed(); failed != 0 {
+			return fmt.Errorf(util.Msg("%d test case(s) failed"), failed)
+		}
+
+		return nil
+	},
+}
+
+type templateLintResponse struct {
+	Templates int           `json:"templates"`
+	Issues    common.Issues `json:"issues"`
+}
+
+func (r templateLintResponse) print(w io.Writer) {
+	errorCount, warningCount := 0, 0
+
+	for _, issue := range r.Issues {
+		location := issue.Field
+		if issue.Line != 0 {
+			location = fmt.Sprintf("%s:%d", location, issue.Line)
+		}
+
+		fmt.Fprintf(w, "%s: %s: %s\n", location, issue.Level, issue.Message)
+
+		if issue.Level == common.IssueLevelError {
+			errorCount++
+		} else {
+			warningCount++
+		}
+	}
+
+	fmt.Fprintf(w, util.Msg("%d template(s) checked, %d error(s), %d warning(s)\n"),
+		r.Templates, errorCount, warningCount)
+}
+
+const (
+	templateTestOk      = "ok"
+	templateTestFailed  = "fail"
+	templateTestUpdated = "updated"
+	templateTestNoCases = "no-cases"
+)
+
+type templateTestResponse struct {
+	Cases []templateTestCase `json:"cases"`
+}
+
+type templateTestCase struct {
+	Name       string            `json:"name"`
+	Status     string            `json:"status"`
+	Error      string            `json:"error,omitempty"`
+	Mismatches []golden.Mismatch `json:"mismatches,omitempty"`
+}
+
+func (r templateTestResponse) print(w io.Writer) {
+	for _, item := range r.Cases {
+		switch item.Status {
+		case templateTestNoCases:
+			fmt.Fprintf(w, util.Msg("?    %s [no test cases]\n"), item.Name)
+
+		case templateTestFailed:
+			fmt.Fprintf(w, "FAIL %s\n", item.Name)
+			if len(item.Error) != 0 {
+				fmt.Fprintf(w, "    %s\n", item.Error)
+			}
+
+			for _, m := range item.Mismatches {
+				fmt.Fprintln(w, m.String())
+			}
+
+		case templateTestUpdated:
+			fmt.Fprintf(w, util.Msg("ok   %s [updated]\n"), item.Name)
+
+		default:
+			fmt.Fprintf(w, "ok   %s\n", item.Name)
+		}
+	}
+}
+
+func (r templateTestResponse) countFailed() int {
+	count := 0
+	for _, item := range r.Cases {
+		if item.Status == templateTestFailed {
+			count++
+		}
+	}
+
+	return count
+}
+
+// templateTarget is what a template command works on: either directories
+// on disk, or a template known to qtcli. Directories on disk are layered
+// over the known templates, so that they can extend them or use files
+// like '@/common/git.ignore'.
+type templateTarget struct {
+	fs     fs.FS
+	dirs   []string
+	onDisk string
+}
+
+func newTemplateTarget(arg string) (templateTarget, error) {
+	if !util.DirExists(arg) {
+		dir := strings.TrimPrefix(arg, "@")
+		if !util.EntryExistsFS(runner.GeneratorEnv.FS,
+			path.Join(dir, common.TemplateFileName)) {
+			return templateTarget{}, fmt.Errorf(
+				util.Msg("neither a directory nor a template, given = '%v'"), arg)
+		}
+
+		return templateTarget{
+			fs:   runner.GeneratorEnv.FS,
+			dirs: []string{dir},
+		}, nil
+	}
+
+	abs, err := filepath.Abs(arg)
+	if err != nil {
+		return templateTarget{}, err
+	}
+
+	disk := os.DirFS(abs)
+	dirs := []string{}
+
+	err = fs.WalkDir(disk, ".",
+		func(walkingPath string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() && util.EntryExistsFS(disk,
+				path.Join(walkingPath, common.TemplateFileName)) {
+				dirs = append(dirs, walkingPath)
+			}
+
+			return nil
+		})
+	if err != nil {
+		return templateTarget{}, err
+	}
+
+	if len(dirs) == 0 {
+		return templateTarget{}, fmt.Errorf(
+			util.Msg("no template found, dir = '%v'"), arg)
+	}
+
+	return templateTarget{
+		fs:     util.NewLayeredFS(disk, runner.GeneratorEnv.FS),
+		dirs:   dirs,
+		onDisk: arg,
+	}, nil
+}
+
+// display returns how to show a path of the target to the user
+func (t templateTarget) display(name string) string {
+	if len(t.onDisk) == 0 {
+		return "@/" + name
+	}
+
+	return filepath.Join(t.onDisk, filepath.FromSlash(name))
+}
+
+func init() {
+	templateTestCmd.Flags().StringVar(
+		&templateTestCasesDir, "cases", "",
+		util.Msg("Read the test cases from the given directory"))
+	templateTestCmd.Flags().BoolVar(
+		&templateTestUpdate, "update", false,
+		util.Msg("Rewrite the expected files with the current output"))
+
+	templateCmd.AddCommand(templateLintCmd)
+	templateCmd.AddCommand(templateTestCmd)
+	rootCmd.AddCommand(templateCmd)
+}
@@ -5,6 +5,7 @@ package cmds
 
 import (
 	"fmt"
+	"io"
 	"qtcli/common"
 	"qtcli/runner"
 	"qtcli/util"
@@ -41,8 +42,7 @@ var testPromptCmd = &cobra.Command{
 
 				item := p
 				item.Options = options
-				printPreset(item)
-				return nil
+				return printPreset(item)
 			}
 		}
 
@@ -63,8 +63,7 @@ var testDefaultCmd = &cobra.Command{
 
 		for _, p := range runner.Presets.Default.GetAll() {
 			if name == p.TemplateDir {
-				printPreset(p)
-				return nil
+				return printPreset(p)
 			}
 		}
 
@@ -72,9 +71,11 @@ var testDefaultCmd = &cobra.Command{
 	},
 }
 
-func printPreset(p common.PresetData) {
-	fmt.Println(strings.Repeat("-", 40))
-	fmt.Println(p.ToYaml())
+func printPreset(p common.PresetData) error {
+	return printResult(p, func(w io.Writer) {
+		fmt.Fprintln(w, strings.Repeat("-", 40))
+		fmt.Fprintln(w, p.ToYaml())
+	})
 }
 
 func createNotFoundError(name string) error {
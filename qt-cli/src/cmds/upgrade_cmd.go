@@ -0,0 +1,74 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package cmds
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"qtcli/generator"
+	"qtcli/runner"
+	"qtcli/util"
+
+	"github.com/spf13/cobra"
+)
+
+var upgradeDryRun bool
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade [dir]",
+	Short: util.Msg("Apply the changes of a template to a generated project"),
+	Long: util.Msg("Apply the changes of a template to a generated project.\n\n" +
+		"The project is rendered twice with the answers recorded in its " +
+		"'.qtcli/manifest.yml': from the template files it was generated " +
+		"from, and from the current ones. The changes between both are " +
+		"merged into the current files. Where both changed the same " +
+		"lines, the file gets conflict markers to resolve by hand."),
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, _ := os.Getwd()
+		if len(args) != 0 {
+			dir = args[0]
+		}
+
+		dir, err := filepath.Abs(dir)
+		if err != nil {
+			return err
+		}
+
+		projectDir, found := generator.FindManifest(filepath.ToSlash(dir))
+		if !found {
+			return fmt.Errorf(util.Msg(
+				"not a generated project, no manifest found, dir = '%v'"), dir)
+		}
+
+		result, err := generator.NewUpgrader(projectDir).
+			Env(runner.GeneratorEnv).
+			DryRun(upgradeDryRun).
+			Run()
+		if err != nil {
+			return err
+		}
+
+		if err := printResult(result, result.Print); err != nil {
+			return err
+		}
+
+		if result.HasConflicts() && !upgradeDryRun {
+			return errors.New(
+				util.Msg("some files have conflicts to resolve by hand"))
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	upgradeCmd.Flags().BoolVar(
+		&upgradeDryRun, "dry-run", false,
+		util.Msg("Show what would change, without writing anything"))
+
+	rootCmd.AddCommand(upgradeCmd)
+}
@@ -5,22 +5,17 @@ package common
 
 import (
 	"io/fs"
-	"qtcli/assets"
-
-	"github.com/sirupsen/logrus"
 )
 
 const PromptFileName = "prompt.yml"
 const TemplateFileName = "templates.yml"
 const UserPresetFileName = ".qtcli.preset"
+const ProjectPresetFileName = ".qtcli.preset"
+const ProjectPresetDirFileName = ".qtcli/presets.yml"
+const PartialsDirName = "partials"
 
 var TemplatesFS fs.FS
 
 func init() {
-	fs_, err := fs.Sub(assets.Assets, "templates")
-	if err != nil {
-		logrus.Fatal(err)
-	}
-
-	TemplatesFS = fs_
+	UseTemplateDirs(nil)
 }
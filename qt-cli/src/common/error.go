@@ -19,6 +19,10 @@ func (e Error) String() string {
 	return e.Message
 }
 
+func (e Error) Error() string {
+	return e.String()
+}
+
 type IssueLevel string
 
 const (
@@ -30,6 +34,7 @@ type Issue struct {
 	Level   IssueLevel `json:"level"`
 	Field   string     `json:"field"`
 	Message string     `json:"message"`
+	Line    int        `json:"line,omitempty"`
 }
 
 func NewErrorIssue(field, message string) *Issue {
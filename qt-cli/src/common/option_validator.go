@@ -0,0 +1,277 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"qtcli/util"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ValidateOptions checks the given options against the prompt steps.
+// It returns the options converted to what the corresponding prompt would
+// have produced, e.g. "yes" becomes true for a confirm step.
+func ValidateOptions(
+	steps []PromptStep, options util.StringAnyMap) (util.StringAnyMap, Issues) {
+	all := Issues{}
+	normalized := util.StringAnyMap{}
+
+	for _, name := range slices.Sorted(maps.Keys(options)) {
+		value := options[name]
+		step, found := findStep(steps, name)
+		if !found {
+			all = append(all, *NewErrorIssue(
+				name, OptionUnknown+": "+name))
+			continue
+		}
+
+		v, issue := validateOption(step, value)
+		if issue != nil {
+			all = append(all, *issue)
+			continue
+		}
+
+		normalized[name] = v
+	}
+
+	return normalized, all
+}
+
+// ResolveWhenConditions checks the 'when' conditions of the steps, in
+// order, against the options, the way a prompt would. The option of a
+// step whose condition is false gets its default back, since it would
+// not be asked; giving it explicitly, in given, is an error.
+func ResolveWhenConditions(steps []PromptStep,
+	defaults, options, given util.StringAnyMap) (util.StringAnyMap, Issues) {
+	all := Issues{}
+	resolved := maps.Clone(options)
+	expander := util.NewTemplateExpander().Data(resolved)
+
+	for _, step := range steps {
+		expander.Name(fmt.Sprintf("steps:%v", step.Id))
+		asked, err := expander.RunStringToBool(step.When, true)
+		if err != nil {
+			all = append(all, *NewErrorIssue(step.Id, err.Error()))
+			continue
+		}
+
+		if asked {
+			continue
+		}
+
+		if _, found := given[step.Id]; found {
+			all = append(all, *NewErrorIssue(
+				step.Id, OptionNotAsked+": "+step.Id))
+		}
+
+		if value, found := defaults[step.Id]; found {
+			resolved[step.Id] = value
+		} else {
+			delete(resolved, step.Id)
+		}
+	}
+
+	return resolved, all
+}
+
+func validateOption(step PromptStep, value any) (any, *Issue) {
+	switch strings.ToLower(step.CompType) {
+	case "input":
+		s, ok := toOptionString(value)
+		if !ok {
+			return nil, NewErrorIssue(step.Id, OptionNotString+": "+step.Id)
+		}
+
+		tag, err := NewTagFromRules(step.Rules)
+		if err != nil {
+			return nil, NewErrorIssue(step.Id, err.Error())
+		}
+
+		if len(tag) != 0 {
+			if issue := NewStringValidator().Run(step.Id, s, tag); issue != nil {
+				issue.Message = fmt.Sprintf("%s: %s", issue.Message, step.Id)
+				return nil, issue
+			}
+		}
+
+		return s, nil
+
+	case "picker":
+		if item, ok := findItem(step.Items, value); ok {
+			return item, nil
+		}
+
+		return nil, NewErrorIssue(step.Id, fmt.Sprintf(
+			"%s: %s = '%v'", OptionNotInItems, step.Id, value))
+
+	case "choices":
+		picked := []string{}
+
+		for _, entry := range splitChoices(value) {
+			item, ok := findItem(step.Items, entry)
+			if !ok {
+				return nil, NewErrorIssue(step.Id, fmt.Sprintf(
+					"%s: %s = '%v'", OptionNotInItems, step.Id, entry))
+			}
+
+			picked = append(picked, fmt.Sprint(item))
+		}
+
+		return strings.Join(picked, ";"), nil
+
+	case "confirm":
+		if b, ok := toOptionBool(value); ok {
+			return b, nil
+		}
+
+		return nil, NewErrorIssue(step.Id, fmt.Sprintf(
+			"%s: %s = '%v'", OptionNotBoolean, step.Id, value))
+	}
+
+	return nil, NewErrorIssue(step.Id, fmt.Sprintf(
+		"%s: %s = '%v'", OptionInvalidType, step.Id, step.CompType))
+}
+
+// NewTagFromRules converts the 'rules' of an input step
+// to a tag understood by StringValidator
+func NewTagFromRules(rules []PromptInputRules) (string, error) {
+	tags := []string{}
+
+	for _, input := range rules {
+		for name, value := range input {
+			tag, err := newTagFromRule(name, value)
+			if err != nil {
+				return "", err
+			}
+
+			if len(tag) != 0 {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	return strings.Join(tags, ","), nil
+}
+
+func newTagFromRule(name string, value any) (string, error) {
+	aname := strings.ToLower(strings.TrimSpace(name))
+
+	if aname == TagRequired {
+		avalue, ok := value.(bool)
+		if !ok {
+			return "", errors.New(
+				util.Msg("invalid argument: boolean expected"))
+		}
+
+		if avalue {
+			return TagRequired, nil
+		}
+
+		return "", nil
+	}
+
+	if aname == TagMatch {
+		pattern, ok := value.(string)
+		if !ok {
+			return "", errors.New(
+				util.Msg("invalid argument: string expected"))
+		}
+
+		_, err := regexp.Compile(pattern)
+		if err != nil {
+			return "", fmt.Errorf(util.Msg("invalid pattern: '%v'"), pattern)
+		}
+
+		return TagMatch + "=" + pattern, nil
+	}
+
+	return "", nil
+}
+
+// helpers
+func findStep(steps []PromptStep, id string) (PromptStep, bool) {
+	for _, step := range steps {
+		if step.Id == id {
+			return step, true
+		}
+	}
+
+	return PromptStep{}, false
+}
+
+func findItem(items []PromptListItem, value any) (any, bool) {
+	given := fmt.Sprint(value)
+
+	for _, item := range items {
+		v := item.DataOrText()
+		if fmt.Sprint(v) == given {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
+
+func splitChoices(value any) []any {
+	switch v := value.(type) {
+	case []any:
+		return v
+
+	case string:
+		all := []any{}
+		for _, s := range strings.Split(v, ";") {
+			if s = strings.TrimSpace(s); len(s) != 0 {
+				all = append(all, s)
+			}
+		}
+
+		return all
+
+	case nil:
+		return []any{}
+
+	default:
+		return []any{v}
+	}
+}
+
+func toOptionString(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+
+	case int, int64, float64, bool:
+		return fmt.Sprint(v), true
+
+	case nil:
+		return "", true
+	}
+
+	return "", false
+}
+
+func toOptionBool(value any) (bool, bool) {
+	switch v := value.(type) {
+	case bool:
+		return v, true
+
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "true", "yes", "y", "on", "1":
+			return true, true
+
+		case "false", "no", "n", "off", "0":
+			return false, true
+		}
+
+	case int:
+		return v != 0, true
+	}
+
+	return false, false
+}
@@ -0,0 +1,130 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package common
+
+import (
+	"fmt"
+	"qtcli/util"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testSteps = []PromptStep{
+	{
+		Id:       "minimumQtVersion",
+		CompType: "picker",
+		Items:    []PromptListItem{{Text: "6.8"}, {Text: "6.5"}},
+	},
+	{
+		Id:       "features",
+		CompType: "choices",
+		Items:    []PromptListItem{{Text: "Quick"}, {Text: "Widgets"}},
+	},
+	{
+		Id:       "useForm",
+		CompType: "confirm",
+	},
+	{
+		Id:       "className",
+		CompType: "input",
+		Rules: []PromptInputRules{
+			{"required": true},
+			{"match": "^[A-Z]"},
+		},
+	},
+}
+
+func TestValidateOptions(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    any
+		pass     bool
+		expected any
+	}{
+		{"minimumQtVersion", "6.5", true, "6.5"},
+		{"minimumQtVersion", 6.5, true, "6.5"},
+		{"minimumQtVersion", "6.0", false, nil},
+
+		{"features", "Quick;Widgets", true, "Quick;Widgets"},
+		{"features", []any{"Widgets"}, true, "Widgets"},
+		{"features", "", true, ""},
+		{"features", "Quick;Core", false, nil},
+
+		{"useForm", "yes", true, true},
+		{"useForm", "N", true, false},
+		{"useForm", false, true, false},
+		{"useForm", "maybe", false, nil},
+
+		{"className", "MyClass", true, "MyClass"},
+		{"className", "myClass", false, nil},
+		{"className", "", false, nil},
+
+		{"unknownKey", "value", false, nil},
+	}
+
+	for _, tc := range cases {
+		testname := fmt.Sprintf("|%s|%v|%t|", tc.name, tc.value, tc.pass)
+		t.Run(testname, func(t *testing.T) {
+			normalized, issues := ValidateOptions(
+				testSteps, util.StringAnyMap{tc.name: tc.value})
+
+			require.Equal(t, tc.pass, !issues.HasError(), issues.String())
+			if tc.pass {
+				require.Equal(t, tc.expected, normalized[tc.name])
+			} else {
+				require.Equal(t, tc.name, issues[0].Field)
+			}
+		})
+	}
+}
+
+func TestResolveWhenConditions(t *testing.T) {
+	steps := []PromptStep{
+		{Id: "useForm", CompType: "confirm"},
+		{Id: "formName", CompType: "input", When: "{{.useForm}}"},
+		{Id: "formTitle", CompType: "input", When: `{{ne .formName ""}}`},
+	}
+
+	defaults := util.StringAnyMap{
+		"useForm": false, "formName": "", "formTitle": "Title"}
+
+	cases := []struct {
+		options  util.StringAnyMap
+		given    util.StringAnyMap
+		expected util.StringAnyMap
+		fields   []string
+	}{
+		{util.StringAnyMap{"useForm": true, "formName": "Main"},
+			util.StringAnyMap{"formName": "Main"},
+			util.StringAnyMap{"useForm": true, "formName": "Main"},
+			[]string{}},
+		{util.StringAnyMap{"useForm": false, "formName": "Main",
+			"formTitle": "Main window"},
+			util.StringAnyMap{},
+			util.StringAnyMap{"useForm": false, "formName": "",
+				"formTitle": "Title"},
+			[]string{}},
+		{util.StringAnyMap{"useForm": false, "formName": "Main"},
+			util.StringAnyMap{"formName": "Main"},
+			util.StringAnyMap{"useForm": false, "formName": "",
+				"formTitle": "Title"},
+			[]string{"formName"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("|%v|%v|", tc.options, tc.given), func(t *testing.T) {
+			resolved, issues := ResolveWhenConditions(
+				steps, defaults, tc.options, tc.given)
+
+			fields := []string{}
+			for _, issue := range issues {
+				fields = append(fields, issue.Field)
+			}
+
+			require.Equal(t, tc.fields, fields)
+			require.Equal(t, tc.expected, resolved)
+		})
+	}
+}
@@ -5,6 +5,8 @@ package common
 
 import (
 	"fmt"
+	"hash/crc32"
+	"io/fs"
 	"qtcli/util"
 	"strings"
 
@@ -21,17 +23,34 @@ type Preset interface {
 	GetUniqueId() string
 }
 
+// PresetScope tells where a preset comes from
+type PresetScope string
+
+const (
+	PresetScopeProject PresetScope = "project"
+	PresetScopeUser    PresetScope = "user"
+	PresetScopeDefault PresetScope = "default"
+)
+
 type PresetData struct {
-	Name        string            `yaml:"name"`
-	TemplateDir string            `yaml:"template"`
-	Options     util.StringAnyMap `yaml:"options"`
+	Name        string            `yaml:"name" json:"name"`
+	TemplateDir string            `yaml:"template" json:"template"`
+	Options     util.StringAnyMap `yaml:"options" json:"options"`
 
 	// private fields
 	uniqueId     string
 	targetTypeId TargetType
+	scope        PresetScope
 }
 
 func NewPresetData(
+	name, templateDir string, options util.StringAnyMap) PresetData {
+	return NewPresetDataIn(TemplatesFS, name, templateDir, options)
+}
+
+// NewPresetDataIn is like NewPresetData, for a template
+// which is not part of TemplatesFS
+func NewPresetDataIn(fsys fs.FS,
 	name, templateDir string, options util.StringAnyMap) PresetData {
 	p := PresetData{
 		Name:        name,
@@ -39,13 +58,17 @@ func NewPresetData(
 		Options:     options,
 	}
 
-	p.ComputeDerivedFields()
+	p.computeDerivedFieldsIn(fsys)
 	return p
 }
 
 func (p *PresetData) ComputeDerivedFields() {
+	p.computeDerivedFieldsIn(TemplatesFS)
+}
+
+func (p *PresetData) computeDerivedFieldsIn(fsys fs.FS) {
 	targetTypeId := TargetTypeFile
-	templateFile, err := OpenTemplateFileIn(TemplatesFS, p.TemplateDir)
+	templateFile, err := OpenTemplateFileIn(fsys, p.TemplateDir)
 	if err == nil {
 		targetTypeId = templateFile.GetTargetType()
 	}
@@ -78,6 +101,14 @@ func (p PresetData) GetTemplateDir() string {
 	return p.TemplateDir
 }
 
+func (p PresetData) GetScope() PresetScope {
+	return p.scope
+}
+
+func (p *PresetData) SetScope(s PresetScope) {
+	p.scope = s
+}
+
 func (p PresetData) GetOptions() util.StringAnyMap {
 	return p.Options
 }
@@ -86,6 +117,12 @@ func (p PresetData) GetUniqueId() string {
 	return p.uniqueId
 }
 
+// GetRevision returns a checksum of the saved fields of the preset,
+// which changes whenever one of them does
+func (p PresetData) GetRevision() string {
+	return fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte(p.ToYaml())))
+}
+
 func (item PresetData) ToYaml() string {
 	output, err := yaml.Marshal(item)
 	if err != nil {
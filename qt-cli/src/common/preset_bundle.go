@@ -0,0 +1,188 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package common
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"qtcli/util"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ImportPolicy tells what to do with an imported preset
+// whose name is already taken
+type ImportPolicy string
+
+const (
+	ImportFail      ImportPolicy = "fail"
+	ImportRename    ImportPolicy = "rename"
+	ImportOverwrite ImportPolicy = "overwrite"
+)
+
+type ImportState string
+
+const (
+	ImportAdded       ImportState = "added"
+	ImportRenamed     ImportState = "renamed"
+	ImportOverwritten ImportState = "overwritten"
+)
+
+type ImportedPreset struct {
+	Name  string      `json:"name"`
+	Id    string      `json:"id"`
+	From  string      `json:"from,omitempty"` // renamed presets only
+	State ImportState `json:"state"`
+}
+
+func ParseImportPolicy(s string) (ImportPolicy, error) {
+	switch p := ImportPolicy(s); p {
+	case "":
+		return ImportFail, nil
+	case ImportFail, ImportRename, ImportOverwrite:
+		return p, nil
+	}
+
+	return ImportFail, fmt.Errorf(
+		util.Msg("invalid import policy, given = '%v'"), s)
+}
+
+// Export returns a bundle of the presets with the given names,
+// or of all the presets without names. A bundle has the format of
+// a preset file.
+func (f *UserPresetFile) Export(names []string) (UserPresetFileContents, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	bundle := UserPresetFileContents{
+		Version: f.contents.Version,
+		Items:   []PresetData{},
+	}
+
+	if len(names) == 0 {
+		bundle.Items = append(bundle.Items, f.contents.Items...)
+		return bundle, nil
+	}
+
+	for _, name := range names {
+		i := slices.IndexFunc(f.contents.Items, func(item PresetData) bool {
+			return item.Name == name
+		})
+
+		if i < 0 {
+			return UserPresetFileContents{},
+				fmt.Errorf(util.Msg("not found, given = '%v'"), name)
+		}
+
+		bundle.Items = append(bundle.Items, f.contents.Items[i])
+	}
+
+	return bundle, nil
+}
+
+// ReadPresetBundle reads a bundle written by Export, in YAML or JSON
+func ReadPresetBundle(filePath string) (UserPresetFileContents, error) {
+	raw, err := os.ReadFile(filePath)
+	if err != nil {
+		return UserPresetFileContents{}, err
+	}
+
+	return ParsePresetBundle(raw)
+}
+
+func ParsePresetBundle(raw []byte) (UserPresetFileContents, error) {
+	bundle := UserPresetFileContents{}
+	if err := yaml.Unmarshal(raw, &bundle); err != nil {
+		return UserPresetFileContents{}, fmt.Errorf(
+			util.Msg("cannot parse the preset bundle: %w"), err)
+	}
+
+	return bundle, nil
+}
+
+func (c UserPresetFileContents) ToYaml() ([]byte, error) {
+	return yaml.Marshal(c)
+}
+
+// Import adds the presets of the bundle, upgraded like the presets of
+// a file and checked against the templates of fsys. Nothing is added if
+// one of them is invalid, or has a name already taken and the policy is
+// ImportFail. The file is not saved.
+func (f *UserPresetFile) Import(fsys fs.FS,
+	bundle UserPresetFileContents, policy ImportPolicy) ([]ImportedPreset, error) {
+	bundle.Items = slices.Clone(bundle.Items)
+	if _, err := bundle.migrate(fsys); err != nil {
+		return nil, err
+	}
+
+	issues := Issues{}
+	seen := []string{}
+
+	for i, item := range bundle.Items {
+		prefix := fmt.Sprintf("items[%d].", i)
+
+		for _, issue := range ValidatePreset(fsys, item) {
+			issue.Field = prefix + issue.Field
+			issue.Message = fmt.Sprintf("%s: %s", item.Name, issue.Message)
+			issues = append(issues, issue)
+		}
+
+		if slices.Contains(seen, item.Name) {
+			issues = append(issues, *NewErrorIssue(prefix+"name",
+				fmt.Sprintf("%s: %s", item.Name, PresetDuplicatedName)))
+		} else if policy == ImportFail && f.Contains(item.Name) {
+			issues = append(issues, *NewErrorIssue(prefix+"name",
+				fmt.Sprintf("%s: %s", item.Name, ServerPresetAlreadyExists)))
+		}
+
+		seen = append(seen, item.Name)
+	}
+
+	if issues.HasError() {
+		return nil, Error{Message: InputHasIssues, Details: issues}
+	}
+
+	all := []ImportedPreset{}
+	for _, item := range bundle.Items {
+		imported := ImportedPreset{Name: item.Name, State: ImportAdded}
+
+		if f.Contains(item.Name) {
+			switch policy {
+			case ImportOverwrite:
+				imported.State = ImportOverwritten
+				if err := f.Remove(item.Name); err != nil {
+					return nil, err
+				}
+
+			case ImportRename:
+				imported.State = ImportRenamed
+				imported.From = item.Name
+				imported.Name = f.freeName(item.Name)
+			}
+		}
+
+		p := NewPresetDataIn(fsys, imported.Name, item.TemplateDir, item.Options)
+		if err := f.Add(p); err != nil {
+			return nil, err
+		}
+
+		imported.Id = p.GetUniqueId()
+		all = append(all, imported)
+	}
+
+	return all, nil
+}
+
+// freeName returns the given name followed by the first number making it
+// unique, like 'name-2'
+func (f *UserPresetFile) freeName(name string) string {
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s-%d", name, n)
+		if !f.Contains(candidate) {
+			return candidate
+		}
+	}
+}
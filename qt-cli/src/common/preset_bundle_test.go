@@ -0,0 +1,210 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package common
+
+import (
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"qtcli/util"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var bundleTestFS = fstest.MapFS{
+	"app/templates.yml": {Data: []byte(`
+meta:
+  type: project
+files:
+  - in: main.cpp
+`)},
+	"app/prompt.yml": {Data: []byte(`
+steps:
+  - id: qmlRoot
+    type: picker
+    items:
+      - text: Window
+      - text: ApplicationWindow
+  - id: useTranslation
+    type: confirm
+    default: false
+consts:
+  - qtMajorVersion: 6
+`)},
+}
+
+func newBundleTestFile(t *testing.T, items ...PresetData) *UserPresetFile {
+	f := NewUserPresetFile(
+		path.Join(filepath.ToSlash(t.TempDir()), UserPresetFileName))
+	require.NoError(t, f.Open())
+
+	for _, item := range items {
+		require.NoError(t, f.Add(item))
+	}
+
+	return f
+}
+
+func bundleTestPreset(name string, options util.StringAnyMap) PresetData {
+	return NewPresetDataIn(bundleTestFS, name, "app", options)
+}
+
+func TestValidatePreset(t *testing.T) {
+	all := []struct {
+		preset PresetData
+		fields []string
+	}{
+		{bundleTestPreset("mine", util.StringAnyMap{
+			"qmlRoot": "ApplicationWindow", "qtMajorVersion": 6,
+			"useTranslation": nil}), []string{}},
+		{bundleTestPreset("", nil), []string{"name"}},
+		{bundleTestPreset("@app", nil), []string{"name"}},
+		{NewPresetDataIn(bundleTestFS, "mine", "none", nil), []string{"template"}},
+		{bundleTestPreset("mine", util.StringAnyMap{
+			"qmlRoot": "Item", "other": 1}),
+			[]string{"options.other", "options.qmlRoot"}},
+	}
+
+	for _, tc := range all {
+		t.Run(tc.preset.Name, func(t *testing.T) {
+			fields := []string{}
+			for _, issue := range ValidatePreset(bundleTestFS, tc.preset) {
+				fields = append(fields, issue.Field)
+			}
+
+			assert.Equal(t, tc.fields, fields)
+		})
+	}
+}
+
+func TestUserPresetFile_Export(t *testing.T) {
+	f := newBundleTestFile(t,
+		bundleTestPreset("a", nil), bundleTestPreset("b", nil))
+
+	bundle, err := f.Export(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, presetNames(bundle.Items))
+
+	bundle, err = f.Export([]string{"b"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b"}, presetNames(bundle.Items))
+
+	_, err = f.Export([]string{"c"})
+	require.Error(t, err)
+
+	raw, err := bundle.ToYaml()
+	require.NoError(t, err)
+
+	parsed, err := ParsePresetBundle(raw)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b"}, presetNames(parsed.Items))
+}
+
+func TestUserPresetFile_Import(t *testing.T) {
+	bundle := UserPresetFileContents{
+		Version: "1",
+		Items: []PresetData{
+			bundleTestPreset("a", util.StringAnyMap{"qmlRoot": "Window"}),
+			bundleTestPreset("b", nil),
+		},
+	}
+
+	all := []struct {
+		policy   ImportPolicy
+		names    []string
+		imported []ImportedPreset
+	}{
+		{ImportRename, []string{"a", "a-2", "b"}, []ImportedPreset{
+			{Name: "a-2", From: "a", State: ImportRenamed},
+			{Name: "b", State: ImportAdded},
+		}},
+		{ImportOverwrite, []string{"a", "b"}, []ImportedPreset{
+			{Name: "a", State: ImportOverwritten},
+			{Name: "b", State: ImportAdded},
+		}},
+	}
+
+	for _, tc := range all {
+		t.Run(string(tc.policy), func(t *testing.T) {
+			f := newBundleTestFile(t, bundleTestPreset("a", nil))
+
+			imported, err := f.Import(bundleTestFS, bundle, tc.policy)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tc.names, f.GetAllNames())
+
+			for i := range imported {
+				imported[i].Id = ""
+			}
+
+			assert.Equal(t, tc.imported, imported)
+		})
+	}
+
+	t.Run("fail", func(t *testing.T) {
+		f := newBundleTestFile(t, bundleTestPreset("a", nil))
+
+		_, err := f.Import(bundleTestFS, bundle, ImportFail)
+		require.Error(t, err)
+		assert.Equal(t, []string{"a"}, f.GetAllNames())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		f := newBundleTestFile(t)
+		invalid := UserPresetFileContents{Items: []PresetData{
+			bundleTestPreset("a", nil),
+			bundleTestPreset("b", util.StringAnyMap{"qmlRoot": "Item"}),
+			bundleTestPreset("a", nil),
+		}}
+
+		_, err := f.Import(bundleTestFS, invalid, ImportRename)
+
+		var e Error
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, "items[1].options.qmlRoot", e.Details[0].Field)
+		assert.Equal(t, "items[2].name", e.Details[1].Field)
+		assert.Equal(t, 0, f.GetCount())
+	})
+}
+
+func TestUserPresetFile_OpenReportsStalePresets(t *testing.T) {
+	file := path.Join(filepath.ToSlash(t.TempDir()), UserPresetFileName)
+	require.NoError(t, os.WriteFile(file, []byte(`version: "1"
+items:
+  - name: good
+    template: projects/cpp/qtquick
+    options:
+      qmlRoot: ApplicationWindow
+  - name: stale
+    template: projects/cpp/qtquick
+    options:
+      qqcStyle: Material
+      qmlRoot: Item
+`), 0644))
+
+	f := NewUserPresetFile(file)
+	require.NoError(t, f.Open())
+	assert.Equal(t, 2, f.GetCount())
+
+	fields := []string{}
+	for _, issue := range f.GetIssues() {
+		assert.Equal(t, IssueLevelWarning, issue.Level)
+		fields = append(fields, issue.Field)
+	}
+
+	assert.Equal(t, []string{
+		"items[1].options.qmlRoot", "items[1].options.qqcStyle"}, fields)
+}
+
+func presetNames(items []PresetData) []string {
+	all := []string{}
+	for _, item := range items {
+		all = append(all, item.Name)
+	}
+
+	return all
+}
@@ -0,0 +1,84 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"qtcli/util"
+)
+
+// UserPresetFileVersion is the version of the preset files written
+// by this version of qtcli
+const UserPresetFileVersion = "1"
+
+// presetFileMigration upgrades the contents of a preset file
+// from a version to the next one
+type presetFileMigration struct {
+	from string
+	to   string
+	run  func(c *UserPresetFileContents)
+}
+
+// presetFileMigrations are run in order, so that a file is upgraded
+// step by step up to UserPresetFileVersion. Entries are only appended.
+var presetFileMigrations = []presetFileMigration{
+	// files written by hand, before the version was required
+	{from: "", to: "1", run: func(c *UserPresetFileContents) {}},
+}
+
+var errNewerPresetFile = errors.New(
+	util.Msg("the preset file was saved by a newer version of qtcli"))
+
+// migrate upgrades the contents to UserPresetFileVersion, then applies
+// the migrations of the templates to the options of the presets.
+// It tells if anything changed. Contents of a newer version are
+// left as they are.
+func (c *UserPresetFileContents) migrate(fsys fs.FS) (bool, error) {
+	changed := false
+
+	for _, m := range presetFileMigrations {
+		if c.Version == m.from {
+			m.run(c)
+			c.Version = m.to
+			changed = true
+		}
+	}
+
+	if c.Version != UserPresetFileVersion {
+		return changed, fmt.Errorf("%w, version = '%v'",
+			errNewerPresetFile, c.Version)
+	}
+
+	for i := range c.Items {
+		if migratePresetOptions(fsys, &c.Items[i]) {
+			changed = true
+		}
+	}
+
+	return changed, nil
+}
+
+// migratePresetOptions renames and removes the options of the preset
+// following the migrations of its template
+func migratePresetOptions(fsys fs.FS, p *PresetData) bool {
+	if len(p.Options) == 0 {
+		return false
+	}
+
+	promptFile, err := OpenPromptFileIn(fsys, p.TemplateDir)
+	if err != nil {
+		return false
+	}
+
+	options := maps.Clone(p.Options)
+	if !promptFile.GetContents().MigrateOptions(options) {
+		return false
+	}
+
+	p.Options = options
+	return true
+}
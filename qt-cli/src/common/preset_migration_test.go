@@ -0,0 +1,132 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package common
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"qtcli/util"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromptFileContents_MigrateOptions(t *testing.T) {
+	contents := PromptFileContents{Migrations: []PromptMigration{
+		{Rename: "old", To: "new"},
+		{Remove: "gone"},
+	}}
+
+	all := []struct {
+		options  util.StringAnyMap
+		expected util.StringAnyMap
+		changed  bool
+	}{
+		{util.StringAnyMap{"old": 1, "kept": 2},
+			util.StringAnyMap{"new": 1, "kept": 2}, true},
+		{util.StringAnyMap{"old": 1, "new": 3},
+			util.StringAnyMap{"new": 3}, true},
+		{util.StringAnyMap{"gone": true}, util.StringAnyMap{}, true},
+		{util.StringAnyMap{"new": 1}, util.StringAnyMap{"new": 1}, false},
+	}
+
+	for _, tc := range all {
+		t.Run(fmt.Sprintf("|%v|", tc.options), func(t *testing.T) {
+			changed := contents.MigrateOptions(tc.options)
+			assert.Equal(t, tc.changed, changed)
+			assert.Equal(t, tc.expected, tc.options)
+		})
+	}
+}
+
+func TestUserPresetFile_OpenMigrates(t *testing.T) {
+	file := path.Join(filepath.ToSlash(t.TempDir()), UserPresetFileName)
+	original := []byte(`items:
+  - name: old
+    template: projects/cpp/console
+    options:
+      language: en_US
+      useTranslation: true
+`)
+	require.NoError(t, os.WriteFile(file, original, 0644))
+
+	f := NewUserPresetFile(file)
+	require.NoError(t, f.Open())
+
+	p, err := f.FindByName("old")
+	require.NoError(t, err)
+	assert.Empty(t, p.GetOptions())
+	assert.Empty(t, f.GetIssues())
+
+	// only upgraded in memory when read
+	raw, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, original, raw)
+	assert.False(t, util.EntryExists(file+".bak"))
+
+	require.NoError(t, f.Update(func(f *UserPresetFile) error {
+		return nil
+	}))
+
+	backup, err := os.ReadFile(file + ".bak")
+	require.NoError(t, err)
+	assert.Equal(t, original, backup)
+
+	saved := NewUserPresetFile(file)
+	require.NoError(t, saved.Open())
+	assert.Equal(t, UserPresetFileVersion, saved.contents.Version)
+	assert.False(t, util.EntryExists(file+".bak.1"))
+}
+
+func TestUserPresetFile_OpenMissing(t *testing.T) {
+	file := path.Join(filepath.ToSlash(t.TempDir()), UserPresetFileName)
+
+	f := NewUserPresetFile(file)
+	require.NoError(t, f.Open())
+	require.NoError(t, f.Refresh())
+	assert.Zero(t, f.GetCount())
+	assert.False(t, util.EntryExists(file))
+}
+
+func TestUserPresetFile_NewerVersion(t *testing.T) {
+	file := path.Join(filepath.ToSlash(t.TempDir()), UserPresetFileName)
+	original := []byte(`version: "99"
+items:
+  - name: future
+    template: projects/cpp/console
+`)
+	require.NoError(t, os.WriteFile(file, original, 0644))
+
+	f := NewUserPresetFile(file)
+	require.NoError(t, f.Open())
+	assert.Equal(t, []string{"future"}, f.GetAllNames())
+
+	err := f.Update(func(f *UserPresetFile) error {
+		return f.Remove("future")
+	})
+	require.ErrorIs(t, err, errNewerPresetFile)
+
+	raw, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, original, raw)
+}
+
+func TestUserPresetFile_ImportMigrates(t *testing.T) {
+	f := newBundleTestFile(t)
+	options := util.StringAnyMap{"qmlRoot": "Window", "useTranslation": false}
+	bundle := UserPresetFileContents{Items: []PresetData{
+		NewPresetData("mine", "projects/cpp/qtquick", options),
+	}}
+
+	_, err := f.Import(TemplatesFS, bundle, ImportFail)
+	require.NoError(t, err)
+
+	p, err := f.FindByName("mine")
+	require.NoError(t, err)
+	assert.Equal(t, util.StringAnyMap{"qmlRoot": "Window"}, p.GetOptions())
+	assert.Contains(t, options, "useTranslation")
+}
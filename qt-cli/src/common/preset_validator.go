@@ -0,0 +1,62 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package common
+
+import (
+	"errors"
+	"io/fs"
+	"maps"
+	"strings"
+)
+
+// ValidatePreset checks that a preset can be used to generate: its name
+// is not empty nor reserved, its template exists, and its options match
+// the prompt steps of the template. Issues about options are reported
+// for the 'options.<name>' field.
+func ValidatePreset(fsys fs.FS, p PresetData) Issues {
+	all := Issues{}
+
+	name := strings.TrimSpace(p.Name)
+	if len(name) == 0 {
+		all = append(all, *NewErrorIssue("name", ValidatorTagRequired))
+	} else if strings.HasPrefix(name, "@") {
+		all = append(all, *NewErrorIssue("name", PresetNameReserved))
+	}
+
+	if _, err := OpenTemplateFileIn(fsys, p.TemplateDir); err != nil {
+		all = append(all, *NewErrorIssue("template",
+			PresetNoTemplate+": "+p.TemplateDir))
+		return all
+	}
+
+	steps := []PromptStep{}
+	options := maps.Clone(p.Options)
+	promptFile, err := OpenPromptFileIn(fsys, p.TemplateDir)
+	if err == nil {
+		steps = promptFile.GetContents().Steps
+
+		// constants are saved along with the answers
+		for _, consts := range promptFile.GetContents().Consts {
+			for name := range consts {
+				delete(options, name)
+			}
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		all = append(all, *NewErrorIssue("template", err.Error()))
+		return all
+	}
+
+	// a step without a default value leaves the option unset
+	maps.DeleteFunc(options, func(name string, value any) bool {
+		return value == nil
+	})
+
+	_, issues := ValidateOptions(steps, options)
+	for _, issue := range issues {
+		issue.Field = "options." + issue.Field
+		all = append(all, issue)
+	}
+
+	return all
+}
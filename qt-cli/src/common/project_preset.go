@@ -0,0 +1,35 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package common
+
+import (
+	"path"
+	"qtcli/util"
+)
+
+// FindProjectPresetFile returns the preset file of the project containing
+// dir, that is the closest '.qtcli.preset' or '.qtcli/presets.yml' found
+// by walking up. The walk stops at the home directory, whose preset file
+// belongs to the user.
+func FindProjectPresetFile(dir, homeDir string) (string, bool) {
+	names := []string{ProjectPresetFileName, ProjectPresetDirFileName}
+
+	for dir != path.Clean(homeDir) {
+		for _, name := range names {
+			candidate := path.Join(dir, name)
+			if util.EntryExists(candidate) {
+				return candidate, true
+			}
+		}
+
+		parent := path.Dir(dir)
+		if parent == dir {
+			break
+		}
+
+		dir = parent
+	}
+
+	return "", false
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package common
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindProjectPresetFile(t *testing.T) {
+	root := filepath.ToSlash(t.TempDir())
+	home := path.Join(root, "home")
+	repo := path.Join(home, "repo")
+	other := path.Join(root, "other")
+	sub := path.Join(repo, "src", "sub")
+
+	write := func(file string) {
+		require.NoError(t, os.MkdirAll(path.Dir(file), 0755))
+		require.NoError(t, os.WriteFile(file, []byte("items: []\n"), 0644))
+	}
+
+	write(path.Join(home, ProjectPresetFileName))
+	write(path.Join(repo, ProjectPresetDirFileName))
+	write(path.Join(root, ProjectPresetFileName))
+	require.NoError(t, os.MkdirAll(sub, 0755))
+	require.NoError(t, os.MkdirAll(other, 0755))
+
+	all := []struct {
+		dir      string
+		expected string
+	}{
+		{sub, path.Join(repo, ProjectPresetDirFileName)},
+		{repo, path.Join(repo, ProjectPresetDirFileName)},
+		{other, path.Join(root, ProjectPresetFileName)},
+
+		// the preset file of home is the one of the user
+		{home, ""},
+	}
+
+	for _, tc := range all {
+		t.Run(tc.dir, func(t *testing.T) {
+			found, ok := FindProjectPresetFile(tc.dir, home)
+			assert.Equal(t, len(tc.expected) != 0, ok)
+			assert.Equal(t, tc.expected, found)
+		})
+	}
+
+	// the closest file wins
+	write(path.Join(repo, "src", ProjectPresetFileName))
+	found, _ := FindProjectPresetFile(sub, home)
+	assert.Equal(t, path.Join(repo, "src", ProjectPresetFileName), found)
+}
+
+func TestUserPresetFile_Scope(t *testing.T) {
+	file := path.Join(filepath.ToSlash(t.TempDir()), ProjectPresetFileName)
+	require.NoError(t, os.WriteFile(file, []byte(
+		"version: \"1\"\nitems:\n  - name: shared\n"+
+			"    template: projects/cpp/console\n"), 0644))
+
+	f := NewUserPresetFile(file).Scope(PresetScopeProject)
+	require.NoError(t, f.Open())
+
+	p, err := f.FindByName("shared")
+	require.NoError(t, err)
+	assert.Equal(t, PresetScopeProject, p.GetScope())
+
+	require.NoError(t, f.Add(NewPresetData("other", "projects/cpp/console", nil)))
+	p, _ = f.FindByName("other")
+	assert.Equal(t, PresetScopeProject, p.GetScope())
+}
@@ -6,7 +6,9 @@ package common
 import (
 	"fmt"
 	"io/fs"
+	"path"
 	"qtcli/util"
+	"slices"
 
 	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
@@ -19,9 +21,19 @@ type PromptFile struct {
 }
 
 type PromptFileContents struct {
-	Version string              `yaml:"version" json:"version"`
-	Steps   []PromptStep        `yaml:"steps" json:"steps"`
-	Consts  []util.StringAnyMap `yaml:"consts" json:"consts"`
+	Version    string              `yaml:"version" json:"version"`
+	Steps      []PromptStep        `yaml:"steps" json:"steps"`
+	Consts     []util.StringAnyMap `yaml:"consts" json:"consts"`
+	Migrations []PromptMigration   `yaml:"migrations" json:"migrations,omitempty"`
+}
+
+// PromptMigration updates the options saved in presets after a step
+// was renamed or removed: the option named by Rename becomes To, and
+// the option named by Remove is dropped.
+type PromptMigration struct {
+	Rename string `yaml:"rename" json:"rename,omitempty"`
+	To     string `yaml:"to" json:"to,omitempty"`
+	Remove string `yaml:"remove" json:"remove,omitempty"`
 }
 
 type PromptStep struct {
@@ -45,6 +57,14 @@ type PromptListItem struct {
 
 type PromptInputRules map[string]any
 
+func (item PromptListItem) DataOrText() any {
+	if item.Data != nil {
+		return item.Data
+	}
+
+	return item.Text
+}
+
 func NewPromptFileFS(fs fs.FS, filePath string) *PromptFile {
 	return &PromptFile{
 		fs:       fs,
@@ -52,6 +72,46 @@ func NewPromptFileFS(fs fs.FS, filePath string) *PromptFile {
 	}
 }
 
+// OpenPromptFileIn opens the prompt definition of a template directory,
+// merged with the ones of the templates it extends. Steps override the
+// steps of the base with the same id. If none of them has a prompt
+// definition, the error wraps fs.ErrNotExist.
+func OpenPromptFileIn(fsys fs.FS, dir string) (*PromptFile, error) {
+	chain, err := TemplateDirChain(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var merged *PromptFile
+
+	for _, d := range chain {
+		fullPath := path.Join(d, PromptFileName)
+		if !util.EntryExistsFS(fsys, fullPath) {
+			continue
+		}
+
+		f := NewPromptFileFS(fsys, fullPath)
+		if err := f.Open(); err != nil {
+			return nil, err
+		}
+
+		if merged == nil {
+			merged = f
+		} else {
+			merged.contents.merge(f.contents)
+			merged.filePath = f.filePath
+		}
+	}
+
+	if merged == nil {
+		return nil, fmt.Errorf(
+			util.Msg("prompt definition does not exist, dir = '%v': %w"),
+			dir, fs.ErrNotExist)
+	}
+
+	return merged, nil
+}
+
 func (f *PromptFile) Open() error {
 	logrus.Debug(fmt.Sprintf(
 		"reading prompt definition, file = '%v'", f.filePath))
@@ -87,6 +147,50 @@ func (f *PromptFile) GetContents() *PromptFileContents {
 	return &f.contents
 }
 
+func (fc *PromptFileContents) merge(other PromptFileContents) {
+	for _, step := range other.Steps {
+		i := slices.IndexFunc(fc.Steps, func(e PromptStep) bool {
+			return e.Id == step.Id
+		})
+
+		if i < 0 {
+			fc.Steps = append(fc.Steps, step)
+		} else {
+			fc.Steps[i] = step
+		}
+	}
+
+	fc.Version = other.Version
+	fc.Consts = mergeNamedMaps(fc.Consts, other.Consts)
+	fc.Migrations = append(fc.Migrations, other.Migrations...)
+}
+
+// MigrateOptions applies the migrations in order to the given options,
+// and tells if one of them changed something
+func (fc *PromptFileContents) MigrateOptions(options util.StringAnyMap) bool {
+	changed := false
+
+	for _, m := range fc.Migrations {
+		if len(m.Rename) != 0 && len(m.To) != 0 {
+			if value, ok := options[m.Rename]; ok {
+				if _, taken := options[m.To]; !taken {
+					options[m.To] = value
+				}
+
+				delete(options, m.Rename)
+				changed = true
+			}
+		}
+
+		if _, ok := options[m.Remove]; ok && len(m.Remove) != 0 {
+			delete(options, m.Remove)
+			changed = true
+		}
+	}
+
+	return changed
+}
+
 func (fc *PromptFileContents) UpdateDefaultValues(options util.StringAnyMap) {
 	for i, step := range fc.Steps {
 		if value, ok := options[step.Id]; ok {
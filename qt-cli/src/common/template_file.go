@@ -7,8 +7,11 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"maps"
 	"path"
 	"qtcli/util"
+	"slices"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
@@ -21,10 +24,13 @@ type TemplateFile struct {
 }
 
 type TemplateFileContents struct {
-	Version string              `yaml:"version"`
-	Files   []TemplateItem      `yaml:"files"`
-	Fields  []util.StringAnyMap `yaml:"fields"`
-	Meta    TemplateMeta        `yaml:"meta"`
+	Version  string              `yaml:"version"`
+	Extends  string              `yaml:"extends"`
+	Abstract bool                `yaml:"abstract"`
+	Files    []TemplateItem      `yaml:"files"`
+	Fields   []util.StringAnyMap `yaml:"fields"`
+	Meta     TemplateMeta        `yaml:"meta"`
+	Hooks    TemplateHooks       `yaml:"hooks"`
 }
 
 type TemplateMeta struct {
@@ -34,13 +40,31 @@ type TemplateMeta struct {
 }
 
 type TemplateItem struct {
+	Id     string `yaml:"id"`
 	In     string `yaml:"in"`
 	Out    string `yaml:"out"`
 	When   string `yaml:"when"`
 	Bypass bool   `yaml:"bypass"`
 }
 
+type TemplateHooks struct {
+	Post []TemplateHook `yaml:"post"`
+}
+
+// TemplateHook is a command run after the files have been written.
+// 'run' and 'when' are expanded the same way as 'out' and 'when' of files.
+type TemplateHook struct {
+	Name string `yaml:"name"`
+	Run  string `yaml:"run"`
+	When string `yaml:"when"`
+}
+
 func OpenTemplateFile(fs fs.FS, filePath string) (*TemplateFile, error) {
+	return openTemplateFile(fs, filePath, []string{})
+}
+
+func openTemplateFile(
+	fs fs.FS, filePath string, visited []string) (*TemplateFile, error) {
 	if len(filePath) == 0 {
 		return nil, errors.New(util.Msg("cannot determine a file path"))
 	}
@@ -60,6 +84,13 @@ func OpenTemplateFile(fs fs.FS, filePath string) (*TemplateFile, error) {
 		return nil, err
 	}
 
+	if len(template.contents.Extends) != 0 {
+		err = template.extend(visited)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &template, nil
 }
 
@@ -67,6 +98,10 @@ func OpenTemplateFileIn(fs fs.FS, dir string) (*TemplateFile, error) {
 	return OpenTemplateFile(fs, path.Join(dir, TemplateFileName))
 }
 
+func (f *TemplateFile) GetVersion() string {
+	return f.contents.Version
+}
+
 func (f *TemplateFile) GetTypeName() string {
 	return f.contents.Meta.Type
 }
@@ -83,6 +118,14 @@ func (f *TemplateFile) GetFields() []util.StringAnyMap {
 	return f.contents.Fields
 }
 
+func (f *TemplateFile) GetPostHooks() []TemplateHook {
+	return f.contents.Hooks.Post
+}
+
+func (f *TemplateFile) IsAbstract() bool {
+	return f.contents.Abstract
+}
+
 func (f *TemplateFile) GetMeta() TemplateMeta {
 	return f.contents.Meta
 }
@@ -103,3 +146,175 @@ func (f *TemplateFile) open() error {
 
 	return nil
 }
+
+// extend merges the base template into this one. Files of the base are
+// kept as they are, unless this template has an item with the same id.
+// Without an explicit id, the 'out' of an item, or its 'in', is the id.
+func (f *TemplateFile) extend(visited []string) error {
+	dir := path.Dir(f.filePath)
+	baseDir := normalizeTemplateDir(f.contents.Extends)
+
+	visited = append(visited, dir)
+	if slices.Contains(visited, baseDir) {
+		return fmt.Errorf(
+			util.Msg("template inherits from itself, chain = '%v'"),
+			strings.Join(append(visited, baseDir), " -> "))
+	}
+
+	base, err := openTemplateFile(
+		f.fs, path.Join(baseDir, TemplateFileName), visited)
+	if err != nil {
+		return err
+	}
+
+	// files of the base are read from the base directory
+	files := []TemplateItem{}
+	for _, item := range base.contents.Files {
+		item.Id = item.GetId()
+		if !strings.HasPrefix(item.In, "@/") {
+			item.In = "@/" + path.Join(baseDir, item.In)
+		}
+
+		files = append(files, item)
+	}
+
+	for _, item := range f.contents.Files {
+		i := slices.IndexFunc(files, func(e TemplateItem) bool {
+			return e.Id == item.GetId()
+		})
+
+		if i < 0 {
+			files = append(files, item)
+		} else {
+			files[i] = item
+		}
+	}
+
+	hooks := slices.Clone(base.contents.Hooks.Post)
+	for _, hook := range f.contents.Hooks.Post {
+		i := slices.IndexFunc(hooks, func(e TemplateHook) bool {
+			return e.GetId() == hook.GetId()
+		})
+
+		if i < 0 {
+			hooks = append(hooks, hook)
+		} else {
+			hooks[i] = hook
+		}
+	}
+
+	meta := base.contents.Meta
+	if len(f.contents.Meta.Type) != 0 {
+		meta.Type = f.contents.Meta.Type
+	}
+
+	if len(f.contents.Meta.Title) != 0 {
+		meta.Title = f.contents.Meta.Title
+	}
+
+	if len(f.contents.Meta.Description) != 0 {
+		meta.Description = f.contents.Meta.Description
+	}
+
+	f.contents.Files = files
+	f.contents.Fields = mergeNamedMaps(
+		base.contents.Fields, f.contents.Fields)
+	f.contents.Hooks.Post = hooks
+	f.contents.Meta = meta
+
+	return nil
+}
+
+// GetId returns what identifies the item when a template is extended
+func (item TemplateItem) GetId() string {
+	if len(item.Id) != 0 {
+		return item.Id
+	}
+
+	if len(item.Out) != 0 {
+		return item.Out
+	}
+
+	return item.In
+}
+
+func (hook TemplateHook) GetId() string {
+	if len(hook.Name) != 0 {
+		return hook.Name
+	}
+
+	return hook.Run
+}
+
+// TemplateDirChain returns the given template directory preceded by all
+// the directories it extends, the furthest base first
+func TemplateDirChain(fs fs.FS, dir string) ([]string, error) {
+	chain := []string{dir}
+
+	for {
+		filePath := path.Join(chain[0], TemplateFileName)
+		if !util.EntryExistsFS(fs, filePath) {
+			return chain, nil
+		}
+
+		raw, err := util.ReadAllFromFS(fs, filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		var contents TemplateFileContents
+		if err = yaml.Unmarshal(raw, &contents); err != nil {
+			return nil, err
+		}
+
+		if len(contents.Extends) == 0 {
+			return chain, nil
+		}
+
+		baseDir := normalizeTemplateDir(contents.Extends)
+		if slices.Contains(chain, baseDir) {
+			return nil, fmt.Errorf(
+				util.Msg("template inherits from itself, chain = '%v'"),
+				strings.Join(append([]string{baseDir}, chain...), " <- "))
+		}
+
+		chain = append([]string{baseDir}, chain...)
+	}
+}
+
+func normalizeTemplateDir(dir string) string {
+	return path.Clean(strings.TrimPrefix(strings.TrimSpace(dir), "@/"))
+}
+
+// mergeNamedMaps overrides the entries of base by the ones of other
+// with the same name, in place, and appends the rest in order
+func mergeNamedMaps(
+	base []util.StringAnyMap, other []util.StringAnyMap) []util.StringAnyMap {
+	merged := []util.StringAnyMap{}
+	for _, m := range base {
+		merged = append(merged, maps.Clone(m))
+	}
+
+	for _, m := range other {
+		rest := util.StringAnyMap{}
+
+		for name, value := range m {
+			i := slices.IndexFunc(merged, func(e util.StringAnyMap) bool {
+				_, ok := e[name]
+				return ok
+			})
+
+			if i < 0 {
+				rest[name] = value
+			} else {
+				merged[i][name] = value
+			}
+		}
+
+		if len(rest) != 0 {
+			merged = append(merged, rest)
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,147 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package common
+
+import (
+	"io/fs"
+	"qtcli/util"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+var extendsTestFS = fstest.MapFS{
+	"base/templates.yml": {Data: []byte(`
+abstract: true
+meta:
+  type: project
+  title: Base
+files:
+  - in: CMakeLists.txt
+  - in: main.cpp
+  - in: '@/common/git.ignore'
+    out: .gitignore
+fields:
+  - target: '{{.name}}'
+  - version: 1
+hooks:
+  post:
+    - name: git
+      run: git init
+`)},
+	"base/prompt.yml": {Data: []byte(`
+steps:
+  - id: minimumQtVersion
+    type: input
+    default: '6.2'
+  - id: useTranslation
+    type: confirm
+    default: false
+consts:
+  - qtMajorVersion: 6
+`)},
+	"derived/templates.yml": {Data: []byte(`
+extends: base
+meta:
+  title: Derived
+files:
+  - in: main.cpp
+  - in: Main.qml
+fields:
+  - version: 2
+  - extra: yes
+hooks:
+  post:
+    - name: chmod
+      run: chmod +x run.sh
+`)},
+	"derived/prompt.yml": {Data: []byte(`
+steps:
+  - id: minimumQtVersion
+    type: input
+    default: '6.5'
+  - id: qmlRoot
+    type: input
+    default: Window
+`)},
+	"self/templates.yml": {Data: []byte(`
+extends: loop
+`)},
+	"loop/templates.yml": {Data: []byte(`
+extends: self
+`)},
+}
+
+func TestTemplateFile_Extends(t *testing.T) {
+	base, err := OpenTemplateFileIn(extendsTestFS, "base")
+	require.NoError(t, err)
+	require.True(t, base.IsAbstract())
+
+	derived, err := OpenTemplateFileIn(extendsTestFS, "derived")
+	require.NoError(t, err)
+	require.False(t, derived.IsAbstract())
+
+	ins := []string{}
+	for _, item := range derived.GetFileItems() {
+		ins = append(ins, item.In)
+	}
+
+	require.Equal(t, []string{
+		"@/base/CMakeLists.txt",
+		"main.cpp",
+		"@/common/git.ignore",
+		"Main.qml",
+	}, ins)
+
+	require.Equal(t, []util.StringAnyMap{
+		{"target": "{{.name}}"},
+		{"version": 2},
+		{"extra": "yes"},
+	}, derived.GetFields())
+
+	hooks := []string{}
+	for _, hook := range derived.GetPostHooks() {
+		hooks = append(hooks, hook.Name)
+	}
+
+	require.Equal(t, []string{"git", "chmod"}, hooks)
+	require.Equal(t, TargetTypeProject, derived.GetTargetType())
+	require.Equal(t, "Derived", derived.GetMeta().Title)
+}
+
+func TestTemplateFile_ExtendsItself(t *testing.T) {
+	_, err := OpenTemplateFileIn(extendsTestFS, "self")
+	require.ErrorContains(t, err, "inherits from itself")
+
+	_, err = TemplateDirChain(extendsTestFS, "self")
+	require.ErrorContains(t, err, "inherits from itself")
+}
+
+func TestPromptFile_Extends(t *testing.T) {
+	chain, err := TemplateDirChain(extendsTestFS, "derived")
+	require.NoError(t, err)
+	require.Equal(t, []string{"base", "derived"}, chain)
+
+	f, err := OpenPromptFileIn(extendsTestFS, "derived")
+	require.NoError(t, err)
+
+	require.Equal(t, util.StringAnyMap{
+		"minimumQtVersion": "6.5",
+		"useTranslation":   false,
+		"qmlRoot":          "Window",
+		"qtMajorVersion":   6,
+	}, f.ExtractDefaults())
+
+	ids := []string{}
+	for _, step := range f.GetContents().Steps {
+		ids = append(ids, step.Id)
+	}
+
+	require.Equal(t,
+		[]string{"minimumQtVersion", "useTranslation", "qmlRoot"}, ids)
+
+	_, err = OpenPromptFileIn(extendsTestFS, "self/none")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+}
@@ -0,0 +1,97 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package common
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"qtcli/assets"
+	"qtcli/util"
+
+	"github.com/sirupsen/logrus"
+)
+
+const TemplatePathEnvName = "QTCLI_TEMPLATE_PATH"
+
+// FindTemplateDirs returns the template directories on disk, ordered from
+// the highest priority to the lowest:
+//
+//  1. the given directories, e.g. from '--templates-dir'
+//  2. the entries of QTCLI_TEMPLATE_PATH, from left to right
+//  3. the per-user directory, e.g. '~/.config/qtcli/templates'
+//
+// Directories that do not exist are skipped.
+func FindTemplateDirs(given []string) []string {
+	candidates := []string{}
+	candidates = append(candidates, given...)
+	candidates = append(candidates,
+		filepath.SplitList(os.Getenv(TemplatePathEnvName))...)
+
+	if configDir, err := os.UserConfigDir(); err == nil {
+		candidates = append(candidates,
+			filepath.Join(configDir, "qtcli", "templates"))
+	}
+
+	found := []string{}
+	seen := map[string]bool{}
+
+	for _, dir := range candidates {
+		if len(dir) == 0 {
+			continue
+		}
+
+		abs, err := filepath.Abs(dir)
+		if err != nil || seen[abs] {
+			continue
+		}
+
+		seen[abs] = true
+		if !util.DirExists(abs) {
+			logrus.Debug(fmt.Sprintf(
+				"skipping template directory, not found = '%v'", abs))
+			continue
+		}
+
+		found = append(found, abs)
+	}
+
+	return found
+}
+
+// NewTemplatesFS layers the given template directories on top of the
+// embedded templates. A template directory (one that contains
+// templates.yml) always comes as a whole from the first root defining it,
+// so '@projects/cpp/qtquick' in a user directory replaces the embedded one
+// instead of being merged with it. Shared files outside template
+// directories, such as 'common/git.ignore', are resolved per file.
+func NewTemplatesFS(dirs []string) fs.FS {
+	layers := []fs.FS{}
+
+	for _, dir := range dirs {
+		logrus.Debug(fmt.Sprintf("using template directory '%v'", dir))
+		layers = append(layers, os.DirFS(dir))
+	}
+
+	embedded, err := fs.Sub(assets.Assets, "templates")
+	if err != nil {
+		logrus.Fatal(err)
+	}
+
+	layers = append(layers, embedded)
+	return util.NewLayeredFS(layers...).MarkerFile(TemplateFileName)
+}
+
+func UseTemplateDirs(given []string) {
+	TemplatesFS = NewTemplatesFS(FindTemplateDirs(given))
+
+	// shared partials, available to every template
+	partials, err := util.LoadPartials(TemplatesFS, PartialsDirName)
+	if err != nil {
+		logrus.Warn("cannot load partials: ", err)
+	}
+
+	util.UseDefaultPartials(partials)
+}
@@ -21,6 +21,19 @@ const (
 	ValidatorDirWillCreated     = "The directory will be created"
 	ValidatorDirInvalid         = "The directory path is invalid"
 
+	OptionUnknown     = "Unknown option"
+	OptionNotString   = "A text value is expected"
+	OptionNotBoolean  = "A boolean value is expected"
+	OptionNotInItems  = "The value is not one of the available items"
+	OptionInvalidType = "The prompt type is invalid"
+	OptionNotAsked    = "The option is not asked, since the 'when' condition of its step is false"
+
+	PresetNameReserved   = "Preset names starting with '@' are reserved"
+	PresetNoTemplate     = "The template does not exist"
+	PresetDuplicatedName = "The preset name is given more than once"
+
+	GeneratorNothingWritten = "no files were written"
+
 	InputOkay      = "Input validation passed successfully"
 	InputHasIssues = "Cannot validate input"
 
@@ -28,8 +41,18 @@ const (
 	ServerNoPresets           = "Cannot find presets"
 	ServerNoTemplateFile      = "Cannot open the template file"
 	ServerClosing             = "The server is shutting down"
+	ServerUnauthorized        = "The session token of the server is missing or wrong"
 	ServerPresetDeleted       = "The preset has been deleted"
 	ServerPresetAlreadyExists = "The preset name is already taken"
+	ServerPresetChanged       = "The preset has been changed since it was read"
+	ServerNoJob               = "Cannot find the job"
+	ServerJobFinished         = "The job has already finished"
+
+	ServerConflictPromptUnsupported = "The 'prompt' conflict policy is not supported by the server"
+
+	StatusCancelled      = "Cancelled"
+	StatusNothingToDo    = "Nothing to do"
+	StatusPresetsCleared = "All user presets have been removed"
 
 	ServerStatusCreated = "Created"
 	ServerStatusUpdated = "Updated"
@@ -4,66 +4,287 @@
 package common
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"qtcli/util"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
 )
 
+// UserPresetFile is shared by the goroutines of the server: its presets
+// are guarded by mutex, and replaced at once when the file is read again
+// or updated, so that readers never see them half changed
 type UserPresetFile struct {
 	filePath string
+	scope    PresetScope
+
+	mutex    sync.RWMutex
 	contents UserPresetFileContents
+	issues   Issues
+
+	// of the file when last read or written, to know if it changed
+	modTime time.Time
+	size    int64
+
+	// if the contents were upgraded when read
+	migrated bool
 }
 
 type UserPresetFileContents struct {
-	Version string       `yaml:"version"`
-	Items   []PresetData `yaml:"items"`
+	Version string       `yaml:"version" json:"version"`
+	Items   []PresetData `yaml:"items" json:"items"`
 }
 
 func NewUserPresetFile(filePath string) *UserPresetFile {
 	return &UserPresetFile{
 		filePath: filePath,
+		scope:    PresetScopeUser,
 	}
 }
 
+// Scope changes what the presets of the file are reported to come from
+func (f *UserPresetFile) Scope(s PresetScope) *UserPresetFile {
+	f.scope = s
+	return f
+}
+
+func (f *UserPresetFile) GetScope() PresetScope {
+	return f.scope
+}
+
+// Open reads the presets of the file. A missing file has no presets,
+// and a file in an older format is upgraded in memory only: nothing is
+// written until the presets are changed with Update.
 func (f *UserPresetFile) Open() error {
 	logrus.Debug(fmt.Sprintf(
 		"reading user presets, file = '%v'", f.filePath))
 
 	if len(f.filePath) == 0 {
 		return fmt.Errorf(
-			"internal error: cannot create a preset file, invalid path")
+			"internal error: cannot open a preset file, invalid path")
+	}
+
+	return f.reload()
+}
+
+// reload reads the presets of the file and checks them, then replaces
+// the ones in memory
+func (f *UserPresetFile) reload() error {
+	loaded := f.emptyCopy()
+	if err := loaded.loadOrWarn(); err != nil {
+		return err
 	}
 
-	if !util.EntryExists(f.filePath) {
-		f.contents.Version = "1"
-		f.contents.Items = []PresetData{}
-		err := f.Save()
-		if err != nil {
+	loaded.validate()
+	f.replaceWith(loaded)
+	return nil
+}
+
+// emptyCopy returns a file of the same path and scope, with no presets,
+// to read the file into without changing the presets in use
+func (f *UserPresetFile) emptyCopy() *UserPresetFile {
+	return &UserPresetFile{filePath: f.filePath, scope: f.scope}
+}
+
+// replaceWith makes the presets read or updated in other the ones of f
+func (f *UserPresetFile) replaceWith(other *UserPresetFile) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.contents = other.contents
+	f.issues = other.issues
+	f.modTime = other.modTime
+	f.size = other.size
+	f.migrated = other.migrated
+}
+
+// load reads the presets from the file, upgraded to the current format.
+// A missing file has no presets. A file saved by a newer qtcli is read
+// as it is, and fails with errNewerPresetFile.
+func (f *UserPresetFile) load() error {
+	contents := UserPresetFileContents{
+		Version: UserPresetFileVersion,
+		Items:   []PresetData{},
+	}
+
+	raw, err := os.ReadFile(f.filePath)
+	if err == nil {
+		contents.Version = ""
+		if err := yaml.Unmarshal(raw, &contents); err != nil {
 			return err
 		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	if contents.Items == nil {
+		contents.Items = []PresetData{}
+	}
+
+	for i := range contents.Items {
+		contents.Items[i].ComputeDerivedFields()
+		contents.Items[i].scope = f.scope
+	}
+
+	migrated, err := contents.migrate(TemplatesFS)
+
+	f.contents = contents
+	f.migrated = migrated
+	f.stamp()
+	return err
+}
+
+// loadOrWarn is like load, but presets saved by a newer qtcli
+// can still be used, though not changed
+func (f *UserPresetFile) loadOrWarn() error {
+	err := f.load()
+	if errors.Is(err, errNewerPresetFile) {
+		logrus.Warn(fmt.Sprintf(util.Msg("%v, file = '%v'"), err, f.filePath))
+		return nil
 	}
 
+	return err
+}
+
+// backup copies the file aside before it is rewritten in a new format
+func (f *UserPresetFile) backup() error {
 	raw, err := os.ReadFile(f.filePath)
-	if err != nil {
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	backupPath := util.NextBackupPath(f.filePath)
+	if _, err := util.WriteAll(raw, backupPath); err != nil {
 		return err
 	}
 
-	err = yaml.Unmarshal(raw, &f.contents)
+	logrus.Info(fmt.Sprintf(util.Msg(
+		"upgrading presets, file = '%v', backup = '%v'"),
+		f.filePath, backupPath))
+
+	return nil
+}
+
+// stamp remembers the state of the file, for Refresh; the mutex must be
+// held unless f is not shared yet
+func (f *UserPresetFile) stamp() {
+	f.modTime, f.size = time.Time{}, 0
+	if info, err := os.Stat(f.filePath); err == nil {
+		f.modTime = info.ModTime()
+		f.size = info.Size()
+	}
+}
+
+// Refresh reads the presets again if the file was changed since it
+// was last read or written, by another process for instance
+func (f *UserPresetFile) Refresh() error {
+	if len(f.filePath) == 0 {
+		return nil
+	}
+
+	// a missing file is the same as before if it was missing then too
+	var modTime time.Time
+	var size int64
+	if info, err := os.Stat(f.filePath); err == nil {
+		modTime, size = info.ModTime(), info.Size()
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	f.mutex.RLock()
+	unchanged := modTime.Equal(f.modTime) && size == f.size
+	f.mutex.RUnlock()
+
+	if unchanged {
+		return nil
+	}
+
+	return f.reload()
+}
+
+// Update runs change on the presets as currently saved, then saves them.
+// The file is locked meanwhile and read again first, so that the changes
+// made by other processes since it was opened are not lost. Nothing is
+// saved if change fails, or if the file was saved by a newer qtcli.
+// A file upgraded when read is backed up first. change is given a copy
+// of the file, whose presets replace the ones of f once saved.
+func (f *UserPresetFile) Update(change func(f *UserPresetFile) error) error {
+	if len(f.filePath) == 0 {
+		return fmt.Errorf(
+			"internal error: cannot update a preset file, invalid path")
+	}
+
+	lock, err := util.LockFile(f.filePath)
 	if err != nil {
 		return err
 	}
 
-	for i := range f.contents.Items {
-		f.contents.Items[i].ComputeDerivedFields()
+	defer lock.Unlock()
+
+	updated := f.emptyCopy()
+	if err := updated.load(); err != nil {
+		return err
+	}
+
+	if err := change(updated); err != nil {
+		return err
+	}
+
+	if updated.migrated {
+		if err := updated.backup(); err != nil {
+			return err
+		}
 	}
 
+	if err := updated.Save(); err != nil {
+		return err
+	}
+
+	updated.migrated = false
+	updated.issues = f.GetIssues()
+	f.replaceWith(updated)
 	return nil
 }
 
+// validate checks the presets against their templates, which may have
+// changed since the presets were saved. Problems are not errors, so that
+// the other presets can still be used.
+func (f *UserPresetFile) validate() {
+	f.issues = Issues{}
+
+	for i, item := range f.contents.Items {
+		for _, issue := range ValidatePreset(TemplatesFS, item) {
+			issue.Level = IssueLevelWarning
+			issue.Field = fmt.Sprintf("items[%d].%s", i, issue.Field)
+			f.issues = append(f.issues, issue)
+
+			logrus.Warn(fmt.Sprintf(
+				util.Msg("preset '%v' in '%v': %v"),
+				item.Name, f.filePath, issue.Message))
+		}
+	}
+}
+
+// GetIssues returns the problems found in the presets when the file
+// was opened
+func (f *UserPresetFile) GetIssues() Issues {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	return f.issues
+}
+
 func (f *UserPresetFile) FindByUniqueId(id string) (PresetData, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
 	for _, item := range f.contents.Items {
 		if item.GetUniqueId() == id {
 			return item, nil
@@ -75,6 +296,9 @@ func (f *UserPresetFile) FindByUniqueId(id string) (PresetData, error) {
 }
 
 func (f *UserPresetFile) FindByName(name string) (PresetData, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
 	for _, item := range f.contents.Items {
 		if item.Name == name {
 			return item, nil
@@ -86,6 +310,9 @@ func (f *UserPresetFile) FindByName(name string) (PresetData, error) {
 }
 
 func (f *UserPresetFile) Contains(name string) bool {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
 	for _, item := range f.contents.Items {
 		if item.Name == name {
 			return true
@@ -100,10 +327,16 @@ func (f *UserPresetFile) GetFilePath() string {
 }
 
 func (f *UserPresetFile) GetCount() int {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
 	return len(f.contents.Items)
 }
 
 func (f *UserPresetFile) GetAllNames() []string {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
 	all := []string{}
 
 	for _, item := range f.contents.Items {
@@ -114,11 +347,17 @@ func (f *UserPresetFile) GetAllNames() []string {
 }
 
 func (f *UserPresetFile) GetAll() []PresetData {
-	return f.contents.Items
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	return slices.Clone(f.contents.Items)
 }
 
 func (f *UserPresetFile) GetItemsOfTargetType(
 	t TargetType) []PresetData {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
 	found := []PresetData{}
 
 	for _, item := range f.contents.Items {
@@ -131,21 +370,33 @@ func (f *UserPresetFile) GetItemsOfTargetType(
 }
 
 func (f *UserPresetFile) Add(data PresetData) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	data.scope = f.scope
 	f.contents.Items = append(f.contents.Items, data)
 	return nil
 }
 
+// Save replaces the file with the presets in memory. Prefer Update
+// when other processes may change the file too.
 func (f *UserPresetFile) Save() error {
+	f.mutex.RLock()
 	output, err := yaml.Marshal(f.contents)
+	f.mutex.RUnlock()
+
 	if err != nil {
 		return err
 	}
 
-	_, err = util.WriteAll([]byte(output), f.filePath)
-	if err != nil {
+	if err := util.WriteAllAtomic(output, f.filePath); err != nil {
 		return err
 	}
 
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.stamp()
 	return nil
 }
 
@@ -158,6 +409,9 @@ func (f *UserPresetFile) Replace(data PresetData) error {
 }
 
 func (f *UserPresetFile) Remove(name string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
 	var found = -1
 
 	for index, item := range f.contents.Items {
@@ -171,20 +425,24 @@ func (f *UserPresetFile) Remove(name string) error {
 		return fmt.Errorf(util.Msg("not found, given = '%v'"), name)
 	}
 
-	f.contents.Items = append(
-		f.contents.Items[:found],
-		f.contents.Items[found+1:]...,
-	)
+	f.contents.Items = slices.Delete(
+		slices.Clone(f.contents.Items), found, found+1)
 
 	return nil
 }
 
 func (f *UserPresetFile) RemoveAll() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
 	f.contents.Items = []PresetData{}
 }
 
 func (f *UserPresetFile) Find(
 	t TargetType, name string) (PresetData, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
 	for _, item := range f.contents.Items {
 		if item.Name == name && t == item.GetTypeId() {
 			return item, nil
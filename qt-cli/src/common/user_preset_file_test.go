@@ -0,0 +1,116 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package common
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserPresetFile_UpdateKeepsOtherChanges(t *testing.T) {
+	file := path.Join(filepath.ToSlash(t.TempDir()), UserPresetFileName)
+
+	a := NewUserPresetFile(file)
+	require.NoError(t, a.Open())
+
+	b := NewUserPresetFile(file)
+	require.NoError(t, b.Open())
+
+	require.NoError(t, b.Update(func(f *UserPresetFile) error {
+		return f.Add(bundleTestPreset("b", nil))
+	}))
+
+	require.NoError(t, a.Update(func(f *UserPresetFile) error {
+		return f.Add(bundleTestPreset("a", nil))
+	}))
+
+	assert.Equal(t, []string{"b", "a"}, a.GetAllNames())
+
+	require.NoError(t, b.Refresh())
+	assert.Equal(t, []string{"b", "a"}, b.GetAllNames())
+
+	err := a.Update(func(f *UserPresetFile) error {
+		f.RemoveAll()
+		return fmt.Errorf("failed")
+	})
+
+	require.Error(t, err)
+	require.NoError(t, b.Refresh())
+	assert.Equal(t, 2, b.GetCount())
+}
+
+func TestUserPresetFile_ConcurrentUpdates(t *testing.T) {
+	file := path.Join(filepath.ToSlash(t.TempDir()), UserPresetFileName)
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			f := NewUserPresetFile(file)
+			assert.NoError(t, f.Update(func(f *UserPresetFile) error {
+				return f.Add(bundleTestPreset(fmt.Sprintf("p%d", i), nil))
+			}))
+		}()
+	}
+
+	wg.Wait()
+
+	f := NewUserPresetFile(file)
+	require.NoError(t, f.Open())
+	assert.Equal(t, 8, f.GetCount())
+}
+
+func TestUserPresetFile_SharedByGoroutines(t *testing.T) {
+	file := path.Join(filepath.ToSlash(t.TempDir()), UserPresetFileName)
+	shared := NewUserPresetFile(file)
+	require.NoError(t, shared.Open())
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(3)
+
+		// changed in this process, and by another one
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, shared.Update(func(f *UserPresetFile) error {
+				return f.Add(bundleTestPreset(fmt.Sprintf("a%d", i), nil))
+			}))
+		}()
+
+		go func() {
+			defer wg.Done()
+			other := NewUserPresetFile(file)
+			assert.NoError(t, other.Update(func(f *UserPresetFile) error {
+				return f.Add(bundleTestPreset(fmt.Sprintf("b%d", i), nil))
+			}))
+		}()
+
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				assert.NoError(t, shared.Refresh())
+				for _, p := range shared.GetAll() {
+					shared.FindByUniqueId(p.GetUniqueId())
+				}
+
+				_, err := shared.Export(nil)
+				assert.NoError(t, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	require.NoError(t, shared.Refresh())
+	assert.Equal(t, 8, shared.GetCount())
+}
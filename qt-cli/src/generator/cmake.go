@@ -0,0 +1,482 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"qtcli/util"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const cmakeListsFileName = "CMakeLists.txt"
+
+// cmakeListKind tells which list of a target a file belongs to
+type cmakeListKind int
+
+const (
+	cmakeListNone cmakeListKind = iota
+	cmakeListSources
+	cmakeListQmlFiles
+	cmakeListResources
+)
+
+var cmakeListKindsByExt = map[string]cmakeListKind{
+	".c":    cmakeListSources,
+	".cc":   cmakeListSources,
+	".cpp":  cmakeListSources,
+	".cxx":  cmakeListSources,
+	".h":    cmakeListSources,
+	".hh":   cmakeListSources,
+	".hpp":  cmakeListSources,
+	".hxx":  cmakeListSources,
+	".ui":   cmakeListSources,
+	".qrc":  cmakeListSources,
+	".qml":  cmakeListQmlFiles,
+	".js":   cmakeListQmlFiles,
+	".mjs":  cmakeListQmlFiles,
+	".png":  cmakeListResources,
+	".jpg":  cmakeListResources,
+	".jpeg": cmakeListResources,
+	".svg":  cmakeListResources,
+	".webp": cmakeListResources,
+	".ttf":  cmakeListResources,
+	".otf":  cmakeListResources,
+	".json": cmakeListResources,
+}
+
+// commands defining the target the files are added to
+var cmakeTargetCommands = []string{
+	"qt_add_executable",
+	"qt_add_library",
+	"qt_add_plugin",
+	"add_executable",
+	"add_library",
+}
+
+// keywords are unquoted arguments in capitals, e.g. 'PRIVATE' or 'QML_FILES'
+var cmakeKeywordPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// cmakeList is where files of a kind are listed: the elements following
+// a keyword, or the arguments of the command following its target
+type cmakeList struct {
+	cmd          *util.CMakeCommand
+	anchor       util.CMakeArg // elements are inserted after it
+	elements     []util.CMakeArg
+	underKeyword bool   // the anchor is the keyword starting the list
+	keyword      string // to add before the elements, if it is missing
+}
+
+// updateCMakeLists adds the files to be written to the nearest
+// CMakeLists.txt above the output directory, in the same project, if
+// any. The edit is added to the result like a file of its own, so that
+// it is written, or previewed, together with the others.
+func (g *Generator) updateCMakeLists(result *ResultData) {
+	cmakeAbs, found := findCMakeLists(result.outputDirAbs, result.workingDir)
+	if !found {
+		return
+	}
+
+	cmakeDir := path.Dir(cmakeAbs)
+	files := []string{}
+
+	for _, item := range result.items {
+		if item.action == FileActionSkipped ||
+			cmakeListKindOf(item.outputFileAbs) == cmakeListNone {
+			continue
+		}
+
+		rel, err := filepath.Rel(cmakeDir, item.outputFileAbs)
+		if err != nil {
+			continue
+		}
+
+		files = append(files, filepath.ToSlash(rel))
+	}
+
+	if len(files) == 0 {
+		return
+	}
+
+	existing, err := os.ReadFile(cmakeAbs)
+	if err != nil {
+		g.warn("cannot read %s: %v", cmakeAbs, err)
+		return
+	}
+
+	contents, added, err := addToCMakeLists(string(existing), files)
+	if err != nil {
+		g.warn("cannot update %s: %v", cmakeAbs, err)
+		return
+	}
+
+	if len(added) == 0 {
+		return
+	}
+
+	outputRel, err := filepath.Rel(result.outputDirAbs, cmakeAbs)
+	if err != nil {
+		return
+	}
+
+	item := ResultItem{
+		outputFileRel: filepath.ToSlash(outputRel),
+		outputFileAbs: cmakeAbs,
+		action:        FileActionUpdated,
+		contents:      []byte(contents),
+	}
+
+	if g.dryRun {
+		item.diff = util.UnifiedDiff(string(existing), contents,
+			path.Join("a", item.outputFileRel),
+			path.Join("b", item.outputFileRel))
+	}
+
+	result.items = append(result.items, item)
+}
+
+// files and directories marking the root of a project
+var projectRootMarkers = []string{
+	path.Join(ManifestDirName, ManifestFileName), ".git", ".hg", ".svn",
+}
+
+// findCMakeLists returns the CMakeLists.txt of dir, or of the closest
+// parent directory having one. The search stops at the root of the
+// project dir belongs to or, outside of a project, at the working
+// directory, so that the CMakeLists.txt of an unrelated parent, like
+// the one of a monorepo or of the home directory, is never edited.
+func findCMakeLists(dir, workingDir string) (string, bool) {
+	dir = path.Clean(dir)
+	limit, found := findProjectRoot(dir)
+	if !found {
+		limit = path.Clean(workingDir)
+	}
+
+	for {
+		candidate := path.Join(dir, cmakeListsFileName)
+		if util.EntryExists(candidate) {
+			return candidate, true
+		}
+
+		parent := path.Dir(dir)
+		if dir == limit || parent == dir {
+			return "", false
+		}
+
+		dir = parent
+	}
+}
+
+// findProjectRoot returns the closest directory above dir, or dir
+// itself, having a manifest or being under version control. The home
+// directory is never the root of a project.
+func findProjectRoot(dir string) (string, bool) {
+	home, _ := os.UserHomeDir()
+	home = filepath.ToSlash(home)
+
+	for dir != home {
+		for _, marker := range projectRootMarkers {
+			if util.EntryExists(path.Join(dir, marker)) {
+				return dir, true
+			}
+		}
+
+		parent := path.Dir(dir)
+		if parent == dir {
+			break
+		}
+
+		dir = parent
+	}
+
+	return "", false
+}
+
+func cmakeListKindOf(file string) cmakeListKind {
+	return cmakeListKindsByExt[strings.ToLower(path.Ext(file))]
+}
+
+// addToCMakeLists returns the contents of a CMakeLists.txt with the
+// files, relative to its directory, added to the lists of the first
+// target it defines. The formatting of the lists is kept: a file is put
+// on a line of its own if the elements of the list are. Files which are
+// already listed, or which have no list to go to, are left out.
+func addToCMakeLists(text string, files []string) (string, []string, error) {
+	cmds, err := util.ParseCMake(text)
+	if err != nil {
+		return text, nil, err
+	}
+
+	targetIndex := slices.IndexFunc(cmds, func(cmd util.CMakeCommand) bool {
+		return slices.Contains(cmakeTargetCommands, strings.ToLower(cmd.Name)) &&
+			len(cmd.Args) != 0
+	})
+
+	if targetIndex < 0 {
+		return text, nil, nil
+	}
+
+	target := cmds[targetIndex].Args[0].Unquoted()
+	lists := map[cmakeListKind]*cmakeList{}
+	pending := map[cmakeListKind][]string{}
+	added := []string{}
+
+	for _, file := range files {
+		kind := cmakeListKindOf(file)
+		if isListedInCMake(cmds, target, file) || slices.Contains(added, file) {
+			continue
+		}
+
+		if _, found := lists[kind]; !found {
+			lists[kind] = findCMakeList(cmds, targetIndex, target, kind)
+		}
+
+		if lists[kind] == nil {
+			logrus.Debugf("no list to add '%s' to, target = '%s'", file, target)
+			continue
+		}
+
+		pending[kind] = append(pending[kind], file)
+		added = append(added, file)
+	}
+
+	// insert from the end, so that the offsets of the others stay valid
+	kinds := []cmakeListKind{}
+	for kind := range pending {
+		kinds = append(kinds, kind)
+	}
+
+	slices.SortFunc(kinds, func(a, b cmakeListKind) int {
+		if lists[a].anchor.End != lists[b].anchor.End {
+			return lists[b].anchor.End - lists[a].anchor.End
+		}
+
+		// at the same place, a new keyword goes after the existing list
+		return len(lists[b].keyword) - len(lists[a].keyword)
+	})
+
+	for _, kind := range kinds {
+		list := lists[kind]
+		insertion := list.format(text, pending[kind])
+		text = text[:list.anchor.End] + insertion + text[list.anchor.End:]
+	}
+
+	return text, added, nil
+}
+
+// findCMakeList returns the list of the target where files of the given
+// kind go, or nil if there is none
+func findCMakeList(cmds []util.CMakeCommand,
+	targetIndex int, target string, kind cmakeListKind) *cmakeList {
+	switch kind {
+	case cmakeListSources:
+		// the sources of the command defining the target, if it has some
+		creating := &cmds[targetIndex]
+		list := &cmakeList{cmd: creating, anchor: creating.Args[0]}
+		for _, arg := range creating.Args[1:] {
+			if !isCMakeKeyword(arg) {
+				list.elements = append(list.elements, arg)
+				list.anchor = arg
+			}
+		}
+
+		if len(list.elements) != 0 {
+			return list
+		}
+
+		// otherwise, the private sources given by target_sources()
+		for i := range cmds {
+			if isCMakeCommandOf(cmds[i], "target_sources", target) {
+				if found := keywordList(&cmds[i], "PRIVATE"); found != nil {
+					return found
+				}
+			}
+		}
+
+		list.anchor = creating.Args[len(creating.Args)-1]
+		return list
+
+	case cmakeListQmlFiles, cmakeListResources:
+		keyword := "QML_FILES"
+		if kind == cmakeListResources {
+			keyword = "RESOURCES"
+		}
+
+		for i := range cmds {
+			if !isCMakeCommandOf(cmds[i], "qt_add_qml_module", target) {
+				continue
+			}
+
+			if found := keywordList(&cmds[i], keyword); found != nil {
+				return found
+			}
+
+			return &cmakeList{
+				cmd:     &cmds[i],
+				anchor:  cmds[i].Args[len(cmds[i].Args)-1],
+				keyword: keyword,
+			}
+		}
+	}
+
+	return nil
+}
+
+// keywordList returns the elements following a keyword of a command
+func keywordList(cmd *util.CMakeCommand, keyword string) *cmakeList {
+	for i, arg := range cmd.Args {
+		if arg.Quoted || arg.Value != keyword {
+			continue
+		}
+
+		list := &cmakeList{cmd: cmd, anchor: arg, underKeyword: true}
+		for _, element := range cmd.Args[i+1:] {
+			if isCMakeKeyword(element) {
+				break
+			}
+
+			list.elements = append(list.elements, element)
+			list.anchor = element
+		}
+
+		return list
+	}
+
+	return nil
+}
+
+// format returns the text to insert after the anchor of the list
+func (l *cmakeList) format(text string, files []string) string {
+	newline := "\n"
+	if strings.Contains(text, "\r\n") {
+		newline = "\r\n"
+	}
+
+	values := []string{}
+	for _, file := range files {
+		values = append(values, quoteCMakeArg(file))
+	}
+
+	multiline := strings.Contains(text[l.anchor.End:l.cmd.Close], "\n")
+	indent := lineIndent(text, l.cmd.Start) + "    "
+
+	if len(l.elements) != 0 {
+		last := l.elements[len(l.elements)-1]
+		before := l.cmd.Open
+		if i := slices.IndexFunc(l.cmd.Args, func(a util.CMakeArg) bool {
+			return a.Start == last.Start
+		}); i > 0 {
+			before = l.cmd.Args[i-1].End
+		}
+
+		multiline = strings.Contains(text[before:last.Start], "\n")
+		indent = lineIndent(text, last.Start)
+	} else if l.underKeyword {
+		indent = lineIndent(text, l.anchor.Start) + "    "
+	}
+
+	if len(l.keyword) != 0 {
+		if !multiline {
+			return " " + l.keyword + " " + strings.Join(values, " ")
+		}
+
+		// like the first argument starting a line, e.g. 'URI'
+		keywordIndent := indent
+		for _, arg := range l.cmd.Args {
+			if startsLine(text, arg.Start) {
+				keywordIndent = lineIndent(text, arg.Start)
+				break
+			}
+		}
+
+		return newline + keywordIndent + l.keyword + newline +
+			keywordIndent + "    " +
+			strings.Join(values, newline+keywordIndent+"    ")
+	}
+
+	if !multiline {
+		return " " + strings.Join(values, " ")
+	}
+
+	return newline + indent + strings.Join(values, newline+indent)
+}
+
+// lineIndent returns the blanks starting the line of the given offset
+func lineIndent(text string, offset int) string {
+	start := strings.LastIndex(text[:offset], "\n") + 1
+	end := start
+	for end < len(text) && (text[end] == ' ' || text[end] == '\t') {
+		end++
+	}
+
+	return text[start:end]
+}
+
+func startsLine(text string, offset int) bool {
+	start := strings.LastIndex(text[:offset], "\n") + 1
+	return len(strings.TrimLeft(text[start:offset], " \t")) == 0
+}
+
+func isCMakeKeyword(arg util.CMakeArg) bool {
+	return !arg.Quoted && cmakeKeywordPattern.MatchString(arg.Value)
+}
+
+func isCMakeCommandOf(cmd util.CMakeCommand, name, target string) bool {
+	return strings.ToLower(cmd.Name) == name &&
+		len(cmd.Args) != 0 && cmd.Args[0].Unquoted() == target
+}
+
+// isListedInCMake tells whether the file is among the sources, QML files
+// or resources of the target, so that a file only mentioned elsewhere,
+// e.g. by install(), is still added
+func isListedInCMake(cmds []util.CMakeCommand, target, file string) bool {
+	for _, cmd := range cmds {
+		if len(cmd.Args) == 0 || cmd.Args[0].Unquoted() != target {
+			continue
+		}
+
+		name := strings.ToLower(cmd.Name)
+		elements := []util.CMakeArg{}
+
+		switch {
+		case slices.Contains(cmakeTargetCommands, name),
+			name == "target_sources":
+			for _, arg := range cmd.Args[1:] {
+				if !isCMakeKeyword(arg) {
+					elements = append(elements, arg)
+				}
+			}
+
+		case name == "qt_add_qml_module":
+			for _, keyword := range []string{"SOURCES", "QML_FILES", "RESOURCES"} {
+				if list := keywordList(&cmd, keyword); list != nil {
+					elements = append(elements, list.elements...)
+				}
+			}
+		}
+
+		for _, arg := range elements {
+			value := strings.TrimPrefix(
+				arg.Unquoted(), "${CMAKE_CURRENT_SOURCE_DIR}/")
+			if value == file {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func quoteCMakeArg(value string) string {
+	if strings.ContainsAny(value, " \t()#\"\\;$") {
+		return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+	}
+
+	return value
+}
@@ -0,0 +1,183 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"qtcli/common"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddToCMakeLists(t *testing.T) {
+	type testCase struct {
+		text     string
+		files    []string
+		expected string
+	}
+
+	all := []testCase{
+		// one source per line
+		{"qt_add_executable(app\n    main.cpp\n)\n",
+			[]string{"foo.h", "foo.cpp"},
+			"qt_add_executable(app\n    main.cpp\n    foo.h\n    foo.cpp\n)\n"},
+
+		// sources on the same line
+		{"add_executable(app WIN32 main.cpp)\n",
+			[]string{"foo.cpp"},
+			"add_executable(app WIN32 main.cpp foo.cpp)\n"},
+
+		// no sources yet
+		{"qt_add_executable(app)\n",
+			[]string{"foo.cpp"},
+			"qt_add_executable(app foo.cpp)\n"},
+
+		// sources given by target_sources()
+		{"qt_add_executable(app)\ntarget_sources(app\n  PRIVATE\n    main.cpp\n  PUBLIC\n    api.h\n)\n",
+			[]string{"foo.cpp"},
+			"qt_add_executable(app)\ntarget_sources(app\n  PRIVATE\n    main.cpp\n    foo.cpp\n  PUBLIC\n    api.h\n)\n"},
+
+		// QML files, resources and sources at once
+		{"qt_add_executable(app\n    main.cpp\n)\n\nqt_add_qml_module(app\n    URI app\n    QML_FILES\n        Main.qml\n)\n",
+			[]string{"Foo.qml", "logo.png", "foo.cpp"},
+			"qt_add_executable(app\n    main.cpp\n    foo.cpp\n)\n\nqt_add_qml_module(app\n    URI app\n    QML_FILES\n        Main.qml\n        Foo.qml\n    RESOURCES\n        logo.png\n)\n"},
+
+		// a QML module without QML files on one line
+		{"qt_add_executable(app main.cpp)\nqt_add_qml_module(app URI app)\n",
+			[]string{"Foo.qml"},
+			"qt_add_executable(app main.cpp)\nqt_add_qml_module(app URI app QML_FILES Foo.qml)\n"},
+
+		// already listed, or with no list to go to
+		{"qt_add_executable(app\n    main.cpp\n    ${CMAKE_CURRENT_SOURCE_DIR}/foo.cpp\n)\n",
+			[]string{"main.cpp", "foo.cpp", "Foo.qml"},
+			"qt_add_executable(app\n    main.cpp\n    ${CMAKE_CURRENT_SOURCE_DIR}/foo.cpp\n)\n"},
+
+		// only mentioned outside of the lists of the target
+		{"qt_add_executable(app\n    main.cpp\n)\ninstall(FILES foo.h DESTINATION include)\nqt_add_qml_module(app\n    URI Foo.qml\n)\n",
+			[]string{"foo.h", "Foo.qml"},
+			"qt_add_executable(app\n    main.cpp\n    foo.h\n)\ninstall(FILES foo.h DESTINATION include)\nqt_add_qml_module(app\n    URI Foo.qml\n    QML_FILES\n        Foo.qml\n)\n"},
+
+		// listed by target_sources() or the QML module
+		{"qt_add_executable(app)\ntarget_sources(app PRIVATE main.cpp)\nqt_add_qml_module(app URI app QML_FILES Main.qml)\n",
+			[]string{"main.cpp", "Main.qml"},
+			"qt_add_executable(app)\ntarget_sources(app PRIVATE main.cpp)\nqt_add_qml_module(app URI app QML_FILES Main.qml)\n"},
+
+		// no target
+		{"project(app)\n", []string{"foo.cpp"}, "project(app)\n"},
+
+		// line endings, quoting and subdirectories
+		{"qt_add_executable(app\r\n\tmain.cpp\r\n)\r\n",
+			[]string{"sub dir/foo.cpp"},
+			"qt_add_executable(app\r\n\tmain.cpp\r\n\t\"sub dir/foo.cpp\"\r\n)\r\n"},
+	}
+
+	for _, tc := range all {
+		name := fmt.Sprintf("|%q|%v|", tc.text, tc.files)
+		t.Run(name, func(t *testing.T) {
+			actual, _, err := addToCMakeLists(tc.text, tc.files)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestGenerator_UpdatesCMakeLists(t *testing.T) {
+	projectDir := t.TempDir()
+	workingDir := filepath.Join(projectDir, "src")
+	cmakeFile := filepath.Join(projectDir, cmakeListsFileName)
+	original := "qt_add_executable(app\n    main.cpp\n)\n"
+
+	require.NoError(t, os.Mkdir(workingDir, 0755))
+	require.NoError(t, os.Mkdir(filepath.Join(projectDir, ".git"), 0755))
+	require.NoError(t, os.WriteFile(cmakeFile, []byte(original), 0644))
+
+	env := &Env{
+		FS:               common.TemplatesFS,
+		FileTypesBaseDir: "types",
+		TemplateFileName: common.TemplateFileName,
+	}
+
+	preset := common.NewPresetData("@cpp/class", "cpp/class",
+		map[string]any{"baseClass": "QObject", "useQObject": false})
+
+	render := func(dryRun, noCMake bool) *Result {
+		return NewGenerator("Foo").
+			Env(env).
+			WorkingDir(filepath.ToSlash(workingDir)).
+			Preset(preset).
+			DryRun(dryRun).
+			NoCMake(noCMake).
+			Render()
+	}
+
+	result := render(true, false)
+	require.True(t, result.Success, result.Error.Message)
+
+	files := result.Data.GetFiles()
+	require.Len(t, files, 3)
+	assert.Equal(t, "../CMakeLists.txt", files[2].Output)
+	assert.Equal(t, FileActionUpdated, files[2].Action)
+	assert.Contains(t, files[2].Diff, "+    src/Foo.cpp\n")
+	assert.Equal(t, []string{"Foo.h", "Foo.cpp"}, result.Data.GetOutputFilesRel())
+
+	contents, _ := os.ReadFile(cmakeFile)
+	assert.Equal(t, original, string(contents))
+
+	result = render(false, true)
+	require.True(t, result.Success, result.Error.Message)
+	require.Len(t, result.Data.GetFiles(), 2)
+
+	for _, name := range []string{"Foo.h", "Foo.cpp"} {
+		require.NoError(t, os.Remove(path.Join(workingDir, name)))
+	}
+
+	result = render(false, false)
+	require.True(t, result.Success, result.Error.Message)
+
+	contents, _ = os.ReadFile(cmakeFile)
+	assert.Equal(t,
+		"qt_add_executable(app\n    main.cpp\n    src/Foo.h\n    src/Foo.cpp\n)\n",
+		string(contents))
+}
+
+func TestFindCMakeLists(t *testing.T) {
+	root := filepath.ToSlash(t.TempDir())
+	for _, dir := range []string{"with/.git", "with/src/sub",
+		"without/.git", "without/src", "plain/src"} {
+		require.NoError(t, os.MkdirAll(path.Join(root, dir), 0755))
+	}
+
+	for _, dir := range []string{"", "with"} {
+		file := path.Join(root, dir, cmakeListsFileName)
+		require.NoError(t, os.WriteFile(file, []byte{}, 0644))
+	}
+
+	cases := []struct {
+		dir        string
+		workingDir string
+		expected   string
+	}{
+		{"with/src/sub", "with/src/sub", "with/CMakeLists.txt"},
+		{"without/src", "without/src", ""},
+		{"plain/src", "plain", ""},
+		{"plain", "", "CMakeLists.txt"},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("|%s|%s|", tc.dir, tc.workingDir), func(t *testing.T) {
+			actual, found := findCMakeLists(
+				path.Join(root, tc.dir), path.Join(root, tc.workingDir))
+			assert.Equal(t, len(tc.expected) != 0, found)
+
+			if found {
+				assert.Equal(t, path.Join(root, tc.expected), actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"fmt"
+	"os"
+	"qtcli/util"
+	"strings"
+)
+
+// ConflictPolicy decides what happens when an output file already exists
+type ConflictPolicy string
+
+const (
+	ConflictFail      ConflictPolicy = "fail"
+	ConflictSkip      ConflictPolicy = "skip"
+	ConflictOverwrite ConflictPolicy = "overwrite"
+	ConflictBackup    ConflictPolicy = "backup"
+	ConflictPrompt    ConflictPolicy = "prompt"
+)
+
+var AllConflictPolicies = []ConflictPolicy{
+	ConflictFail,
+	ConflictSkip,
+	ConflictOverwrite,
+	ConflictBackup,
+	ConflictPrompt,
+}
+
+// ConflictResolver is asked for every existing file when the policy is
+// ConflictPrompt. It returns the policy to apply to that file only.
+type ConflictResolver func(outputFileAbs string) (ConflictPolicy, error)
+
+// FileAction tells what happened, or will happen, to an output file
+type FileAction string
+
+const (
+	FileActionCreated     FileAction = "created"
+	FileActionOverwritten FileAction = "overwritten"
+	FileActionBackedUp    FileAction = "backed-up"
+	FileActionSkipped     FileAction = "skipped"
+
+	// FileActionUpdated is for project files edited to include the
+	// generated files, e.g. CMakeLists.txt
+	FileActionUpdated FileAction = "updated"
+
+	// FileActionConflict is only reported by dry runs, for existing files
+	// which a real run would fail on, or would ask about
+	FileActionConflict FileAction = "conflict"
+)
+
+func ParseConflictPolicy(s string) (ConflictPolicy, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if len(s) == 0 {
+		return ConflictFail, nil
+	}
+
+	for _, p := range AllConflictPolicies {
+		if string(p) == s {
+			return p, nil
+		}
+	}
+
+	return ConflictFail, fmt.Errorf(
+		util.Msg("invalid conflict policy, given = '%v'"), s)
+}
+
+func (p ConflictPolicy) AllowsExisting() bool {
+	return p != ConflictFail && p != ""
+}
+
+func (g *Generator) resolveAction(outputFileAbs string) (FileAction, error) {
+	stat, err := os.Stat(outputFileAbs)
+	if os.IsNotExist(err) {
+		return FileActionCreated, nil
+	}
+
+	if err == nil && stat.IsDir() {
+		return "", fmt.Errorf(
+			"output is an existing directory, %s", outputFileAbs)
+	}
+
+	policy := g.conflictPolicy
+	if g.dryRun && (policy == ConflictFail || policy == ConflictPrompt) {
+		return FileActionConflict, nil
+	}
+
+	if policy == ConflictPrompt {
+		if g.conflictResolver == nil {
+			return "", fmt.Errorf(
+				"cannot ask how to resolve conflict, %s", outputFileAbs)
+		}
+
+		policy, err = g.conflictResolver(outputFileAbs)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	switch policy {
+	case ConflictSkip:
+		return FileActionSkipped, nil
+
+	case ConflictOverwrite:
+		return FileActionOverwritten, nil
+
+	case ConflictBackup:
+		return FileActionBackedUp, nil
+	}
+
+	return "", fmt.Errorf("output already exists, %s", outputFileAbs)
+}
@@ -9,4 +9,11 @@ type Env struct {
 	FS               fs.FS
 	FileTypesBaseDir string
 	TemplateFileName string
+
+	// Version of qtcli, recorded in the manifests of projects
+	Version string
+
+	// StoreDir keeps the template files projects are rendered from, to
+	// render them again on upgrades; nothing is kept if it is empty
+	StoreDir string
 }
@@ -0,0 +1,96 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"qtcli/util"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// EventType tells what an Event reports
+type EventType string
+
+const (
+	EventFileRendered EventType = "file-rendered"
+	EventFileWritten  EventType = "file-written"
+	EventWarning      EventType = "warning"
+	EventHookOutput   EventType = "hook-output"
+)
+
+// Event reports the progress of a generation while it runs.
+// Only the fields of its type are set.
+type Event struct {
+	Type    EventType
+	File    string     // output file, absolute
+	Action  FileAction // for files
+	Backup  string     // for written files which were backed up
+	Hook    string     // name of the hook
+	Message string     // text of a warning, or a line of hook output
+}
+
+func (g *Generator) emit(e Event) {
+	if g.onEvent != nil {
+		g.onEvent(e)
+	}
+}
+
+func (g *Generator) warn(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	logrus.Warn(msg)
+	g.emit(Event{Type: EventWarning, Message: msg})
+}
+
+// checkCancelled fails once the context of the generator is done
+func (g *Generator) checkCancelled() error {
+	if err := g.ctx.Err(); err != nil {
+		return fmt.Errorf(util.Msg("generation cancelled: %w"), err)
+	}
+
+	return nil
+}
+
+// hookOutput keeps the output of a hook, and reports it line by line
+type hookOutput struct {
+	mutex   sync.Mutex
+	all     bytes.Buffer
+	pending []byte
+	emit    func(line string)
+}
+
+func (o *hookOutput) Write(p []byte) (int, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.all.Write(p)
+	o.pending = append(o.pending, p...)
+
+	for {
+		i := bytes.IndexByte(o.pending, '\n')
+		if i < 0 {
+			break
+		}
+
+		o.emit(string(bytes.TrimRight(o.pending[:i], "\r")))
+		o.pending = o.pending[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// flush reports the last line, if it does not end with a newline
+func (o *hookOutput) flush() string {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if len(o.pending) != 0 {
+		o.emit(string(o.pending))
+		o.pending = nil
+	}
+
+	return o.all.String()
+}
@@ -0,0 +1,87 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"context"
+	"path/filepath"
+	"qtcli/common"
+	"qtcli/util"
+	"runtime"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEventsTestGenerator(t *testing.T) (*Generator, string) {
+	env := &Env{
+		FS: fstest.MapFS{
+			"hooked/templates.yml": {Data: []byte(hooksTestTemplate)},
+			"hooked/file.txt":      {Data: []byte("contents")},
+		},
+		TemplateFileName: common.TemplateFileName,
+	}
+
+	dir := t.TempDir()
+	preset := common.NewPresetData(
+		"hooked", "hooked", util.StringAnyMap{"fail": false})
+
+	g := NewGenerator("myfile").
+		Env(env).
+		WorkingDir(filepath.ToSlash(dir)).
+		Preset(preset)
+
+	return g, dir
+}
+
+func TestGenerator_Events(t *testing.T) {
+	g, dir := newEventsTestGenerator(t)
+	file := filepath.ToSlash(filepath.Join(dir, "myfile.txt"))
+
+	events := []Event{}
+	result := g.Events(func(e Event) {
+		events = append(events, e)
+	}).Render()
+	require.True(t, result.Success, result.Error.Message)
+
+	assert.Equal(t, []Event{
+		{Type: EventFileRendered, File: file, Action: FileActionCreated},
+		{Type: EventFileWritten, File: file, Action: FileActionCreated},
+		{Type: EventHookOutput, Hook: "first", Message: runtime.GOOS},
+		{Type: EventHookOutput, Hook: "never", Message: runtime.GOARCH},
+	}, events)
+}
+
+func TestGenerator_Cancelled(t *testing.T) {
+	t.Run("before writing", func(t *testing.T) {
+		g, dir := newEventsTestGenerator(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		result := g.Context(ctx).Render()
+		require.False(t, result.Success)
+		assert.Contains(t, result.Error.Message, common.GeneratorNothingWritten)
+		assert.NoFileExists(t, filepath.Join(dir, "myfile.txt"))
+	})
+
+	t.Run("after writing", func(t *testing.T) {
+		g, dir := newEventsTestGenerator(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		result := g.Context(ctx).Events(func(e Event) {
+			if e.Type == EventFileWritten {
+				cancel()
+			}
+		}).Render()
+		require.True(t, result.Success, result.Error.Message)
+		assert.FileExists(t, filepath.Join(dir, "myfile.txt"))
+
+		for _, h := range result.Data.GetHooks() {
+			assert.Equal(t, HookStatusSkipped, h.Status)
+		}
+	})
+}
@@ -4,8 +4,11 @@
 package generator
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io/fs"
+	"maps"
 	"os"
 	"path"
 	"path/filepath"
@@ -19,18 +22,27 @@ import (
 )
 
 type Generator struct {
-	env        *Env
-	name       string
-	preset     common.Preset
-	workingDir string
-	dryRun     bool
-	context    Context
+	env              *Env
+	name             string
+	preset           common.Preset
+	workingDir       string
+	dryRun           bool
+	conflictPolicy   ConflictPolicy
+	conflictResolver ConflictResolver
+	noHooks          bool
+	noCMake          bool
+	context          Context
+	ctx              context.Context
+	onEvent          func(Event)
+	sources          *recordingFS
 }
 
 type Context struct {
 	data            util.StringAnyMap
 	funcs           template.FuncMap
 	items           []common.TemplateItem
+	hooks           []common.TemplateHook
+	partials        map[string]string
 	outputDirOffset string
 }
 
@@ -39,9 +51,11 @@ func NewGenerator(name string) *Generator {
 	cwd = filepath.ToSlash(cwd)
 
 	return &Generator{
-		name:       name,
-		workingDir: cwd,
-		dryRun:     false,
+		name:           name,
+		workingDir:     cwd,
+		dryRun:         false,
+		conflictPolicy: ConflictFail,
+		ctx:            context.Background(),
 	}
 }
 
@@ -65,15 +79,54 @@ func (g *Generator) DryRun(on bool) *Generator {
 	return g
 }
 
+func (g *Generator) ConflictPolicy(p ConflictPolicy) *Generator {
+	g.conflictPolicy = p
+	return g
+}
+
+func (g *Generator) ConflictResolver(r ConflictResolver) *Generator {
+	g.conflictResolver = r
+	return g
+}
+
+// NoHooks turns off the post-generation hooks of the template
+func (g *Generator) NoHooks(on bool) *Generator {
+	g.noHooks = on
+	return g
+}
+
+// NoCMake turns off adding generated files to the nearest CMakeLists.txt
+func (g *Generator) NoCMake(on bool) *Generator {
+	g.noCMake = on
+	return g
+}
+
+// Context cancels the generation when ctx is done. Nothing is written
+// if it happens before the files are written, and the remaining hooks
+// are skipped if it happens after.
+func (g *Generator) Context(ctx context.Context) *Generator {
+	g.ctx = ctx
+	return g
+}
+
+// Events reports the progress of the generation to fn, which is called
+// from the goroutine of Render
+func (g *Generator) Events(fn func(Event)) *Generator {
+	g.onEvent = fn
+	return g
+}
+
 func (g *Generator) Render() *Result {
 	g.name = strings.TrimSpace(g.name)
 	g.workingDir = strings.TrimSpace(g.workingDir)
 
 	// input validation
 	issues := Validate(ValidatorIn{
-		Name:       g.name,
-		WorkingDir: g.workingDir,
-		TypeId:     g.preset.GetTypeId(),
+		Name:           g.name,
+		WorkingDir:     g.workingDir,
+		TypeId:         g.preset.GetTypeId(),
+		ConflictPolicy: g.conflictPolicy,
+		DryRun:         g.dryRun,
 	})
 
 	if issues.HasError() {
@@ -83,7 +136,15 @@ func (g *Generator) Render() *Result {
 		})
 	}
 
+	for _, issue := range issues {
+		g.emit(Event{Type: EventWarning, Message: issue.Message})
+	}
+
 	// prep.
+	if g.preset.GetTypeId() == common.TargetTypeProject {
+		g.recordSources()
+	}
+
 	if err := g.prepContext(); err != nil {
 		return NewErrorResultFrom(err)
 	}
@@ -94,31 +155,124 @@ func (g *Generator) Render() *Result {
 		return NewErrorResultFrom(err)
 	}
 
+	result.hooks, err = g.runHookNames()
+	if err != nil {
+		return NewErrorResultFrom(err)
+	}
+
 	// check if exists
-	for _, item := range result.items {
+	for i, item := range result.items {
 		if !util.EntryExistsFS(g.env.FS, item.inputFileRel) {
 			return NewErrorResultFrom(
 				fmt.Errorf("file not found, %s", item.inputFileRel))
 		}
 
-		if util.EntryExists(item.outputFileAbs) {
-			return NewErrorResultFrom(
-				fmt.Errorf("output already exists, %s", item.outputFileAbs))
+		action, err := g.resolveAction(item.outputFileAbs)
+		if err != nil {
+			return NewErrorResultFrom(err)
 		}
+
+		result.items[i].action = action
 	}
 
-	// run contents and save
-	for _, item := range result.items {
+	// expand all contents in memory first
+	for i := range result.items {
+		if err := g.checkCancelled(); err != nil {
+			return newNothingWrittenResult(err)
+		}
+
+		item := &result.items[i]
 		if err := g.runContents(item); err != nil {
+			return newNothingWrittenResult(err)
+		}
+
+		g.emit(Event{
+			Type:   EventFileRendered,
+			File:   item.outputFileAbs,
+			Action: item.action,
+		})
+	}
+
+	// new files are added to the project they are generated in
+	if g.preset.GetTypeId() == common.TargetTypeFile && !g.noCMake {
+		g.updateCMakeLists(&result)
+	}
+
+	if g.dryRun {
+		return NewOkayResult(result)
+	}
+
+	// then write them at once, or not at all
+	if err := g.checkCancelled(); err != nil {
+		return newNothingWrittenResult(err)
+	}
+
+	if err := g.commit(&result); err != nil {
+		if errors.Is(err, errRollbackFailed) {
 			return NewErrorResultFrom(err)
 		}
+
+		return newNothingWrittenResult(err)
+	}
+
+	for _, item := range result.items {
+		if item.action != FileActionSkipped {
+			g.emit(Event{
+				Type:   EventFileWritten,
+				File:   item.outputFileAbs,
+				Action: item.action,
+				Backup: item.backupFileAbs,
+			})
+		}
+	}
+
+	if !g.noHooks {
+		g.runHooks(result.hooks, result.outputDirAbs)
+	}
+
+	okay := NewOkayResult(result)
+	okay.Committed = true
+
+	return okay
+}
+
+func (g *Generator) commit(result *ResultData) error {
+	tx := newTransaction()
+
+	for i := range result.items {
+		item := &result.items[i]
+		if item.action == FileActionSkipped {
+			continue
+		}
+
+		if item.action == FileActionBackedUp {
+			item.backupFileAbs = util.NextBackupPath(item.outputFileAbs)
+		}
+
+		tx.Add(item.outputFileAbs, item.contents, item.backupFileAbs)
 	}
 
-	return NewOkayResult(result)
+	// projects record how they were generated
+	if g.preset.GetTypeId() == common.TargetTypeProject {
+		if err := g.addManifest(tx, result); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// recordSources remembers the template files read from now on, which
+// the manifest of the project identifies
+func (g *Generator) recordSources() {
+	env := *g.env
+	g.sources = newRecordingFS(env.FS)
+	env.FS = g.sources
+	g.env = &env
 }
 
 func (g *Generator) prepContext() error {
-	files, fields, err := g.readFilesAndFields()
+	template, err := g.readTemplateFile()
 	if err != nil {
 		return err
 	}
@@ -126,13 +280,18 @@ func (g *Generator) prepContext() error {
 	g.context.data = g.preset.GetOptions()
 	g.context.data["name"] = g.name
 	g.context.funcs = getApi()
-	g.context.items = files
+	g.context.items = template.GetFileItems()
+	g.context.hooks = template.GetPostHooks()
+	g.context.partials, err = g.readPartials()
+	if err != nil {
+		return err
+	}
 	g.context.outputDirOffset = ""
 	if g.preset.GetTypeId() == common.TargetTypeProject {
 		g.context.outputDirOffset = g.name
 	}
 
-	err = g.evalFields(fields)
+	err = g.evalFields(template.GetFields())
 	if err != nil {
 		return err
 	}
@@ -141,7 +300,9 @@ func (g *Generator) prepContext() error {
 }
 
 func (g *Generator) evalFields(fields []util.StringAnyMap) error {
-	expander := util.NewTemplateExpander().Funcs(g.context.funcs)
+	expander := util.NewTemplateExpander().
+		Funcs(g.context.funcs).
+		Partials(g.context.partials)
 
 	for _, field := range fields {
 		for name, expr := range field {
@@ -201,31 +362,40 @@ func (g *Generator) runNames() (ResultData, error) {
 	return result, nil
 }
 
-func (g *Generator) readFilesAndFields() (
-	[]common.TemplateItem, []util.StringAnyMap, error) {
+func (g *Generator) readPartials() (map[string]string, error) {
+	return readPartials(g.env.FS, g.preset.GetTemplateDir())
+}
+
+// newExpander returns an expander set up with the context
+func (g *Generator) newExpander(name string) *util.TemplateExpander {
+	return util.NewTemplateExpander().
+		Name(name).
+		Data(g.context.data).
+		Funcs(g.context.funcs).
+		Partials(g.context.partials)
+}
+
+func (g *Generator) readTemplateFile() (*common.TemplateFile, error) {
 	dir := g.preset.GetTemplateDir()
 	filePath := path.Join(dir, g.env.TemplateFileName)
 
 	if len(dir) == 0 {
-		return []common.TemplateItem{}, []util.StringAnyMap{},
-			errors.New(util.Msg("cannot determine a config file path"))
+		return nil, errors.New(util.Msg("cannot determine a config file path"))
 	}
 
 	if !util.EntryExistsFS(g.env.FS, filePath) {
-		return []common.TemplateItem{}, []util.StringAnyMap{},
-			fmt.Errorf(
-				util.Msg("template definition does not exist, dir = '%v'"), dir)
-	}
-
-	template, err := common.OpenTemplateFile(g.env.FS, filePath)
-	if err != nil {
-		return []common.TemplateItem{}, []util.StringAnyMap{}, err
+		return nil, fmt.Errorf(
+			util.Msg("template definition does not exist, dir = '%v'"), dir)
 	}
 
-	return template.GetFileItems(), template.GetFields(), nil
+	return common.OpenTemplateFile(g.env.FS, filePath)
 }
 
-func (g *Generator) runContents(result ResultItem) error {
+func (g *Generator) runContents(result *ResultItem) error {
+	if result.action == FileActionSkipped {
+		return nil
+	}
+
 	// expand input file contents
 	allBytes, err := util.ReadAllFromFS(g.env.FS, result.inputFileRel)
 
@@ -239,12 +409,9 @@ func (g *Generator) runContents(result ResultItem) error {
 	if result.templateItem.Bypass {
 		output = input
 	} else {
-		expander := util.NewTemplateExpander().
-			Data(g.context.data).
-			Funcs(g.context.funcs)
-
-		output, err = expander.
-			Name(result.outputFileAbs).
+		// fileName is only known to this file, so keep it out of the context
+		output, err = g.newExpander(result.outputFileAbs).
+			Data(maps.Clone(g.context.data)).
 			AddData("fileName", result.outputFileAbs).
 			RunString(input)
 	}
@@ -253,13 +420,18 @@ func (g *Generator) runContents(result ResultItem) error {
 		return err
 	}
 
-	// save to file
-	if !g.dryRun {
-		output = polishOutput(output)
-		_, err = util.WriteAll([]byte(output), result.outputFileAbs)
+	result.contents = []byte(polishOutput(output))
+
+	if g.dryRun && result.action != FileActionCreated {
+		existing, err := os.ReadFile(result.outputFileAbs)
 		if err != nil {
 			return err
 		}
+
+		result.diff = util.UnifiedDiff(
+			string(existing), string(result.contents),
+			path.Join("a", result.outputFileRel),
+			path.Join("b", result.outputFileRel))
 	}
 
 	return nil
@@ -279,11 +451,7 @@ func (g *Generator) createOutputFileRel(
 		return path.Base(file.In), nil
 	}
 
-	out, err := util.NewTemplateExpander().
-		Name(file.In).
-		Data(g.context.data).
-		Funcs(g.context.funcs).
-		RunString(file.Out)
+	out, err := g.newExpander(file.In).RunString(file.Out)
 
 	if err != nil {
 		return out, err
@@ -293,11 +461,34 @@ func (g *Generator) createOutputFileRel(
 }
 
 func (g *Generator) evalWhenCondition(file common.TemplateItem) (bool, error) {
-	return util.NewTemplateExpander().
-		Name(file.In).
-		Data(g.context.data).
-		Funcs(g.context.funcs).
-		RunStringToBool(file.When, true)
+	return g.newExpander(file.In).RunStringToBool(file.When, true)
+}
+
+// readPartials reads the shared partials, and the partials of the template
+// directory and of the templates it extends. Partials of a template
+// override the ones of the templates it extends, and the shared ones.
+func readPartials(fsys fs.FS, dir string) (map[string]string, error) {
+	chain, err := common.TemplateDirChain(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := util.LoadPartials(fsys, common.PartialsDirName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range chain {
+		partials, err := util.LoadPartials(
+			fsys, path.Join(d, common.PartialsDirName))
+		if err != nil {
+			return nil, err
+		}
+
+		maps.Copy(all, partials)
+	}
+
+	return all, nil
 }
 
 func polishOutput(contents string) string {
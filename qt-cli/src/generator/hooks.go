@@ -0,0 +1,135 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"qtcli/util"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const hookTimeout = 5 * time.Minute
+
+// HookStatus tells how a post-generation hook went
+type HookStatus string
+
+const (
+	HookStatusSucceeded HookStatus = "succeeded"
+	HookStatusFailed    HookStatus = "failed"
+	HookStatusSkipped   HookStatus = "skipped"
+)
+
+type HookResult struct {
+	Name     string
+	Command  []string
+	Status   HookStatus
+	ExitCode int
+	Output   string // stdout and stderr combined
+	Error    string
+}
+
+// runHookNames expands the hooks whose 'when' condition is satisfied.
+// It runs before anything is written, so that a broken hook definition
+// does not leave a half-done result behind.
+func (g *Generator) runHookNames() ([]HookResult, error) {
+	all := []HookResult{}
+
+	for _, hook := range g.context.hooks {
+		okay, err := g.newExpander(hook.Run).RunStringToBool(hook.When, true)
+		if err != nil {
+			return nil, err
+		}
+
+		if !okay {
+			logrus.Debug(
+				"skipping hook ",
+				"because 'when' condition was not satisfied")
+			continue
+		}
+
+		line, err := g.newExpander(hook.Run).RunString(hook.Run)
+		if err != nil {
+			return nil, err
+		}
+
+		args, err := util.SplitCommandLine(line)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(args) == 0 {
+			return nil, fmt.Errorf(
+				util.Msg("hook has no command, name = '%v'"), hook.Name)
+		}
+
+		name := hook.Name
+		if len(name) == 0 {
+			name = strings.Join(args, " ")
+		}
+
+		all = append(all, HookResult{
+			Name:    name,
+			Command: args,
+			Status:  HookStatusSkipped,
+		})
+	}
+
+	return all, nil
+}
+
+// runHooks runs the hooks one by one in the output directory.
+// The first failure, or the cancellation of the generation, stops
+// the rest, which are reported as skipped.
+func (g *Generator) runHooks(hooks []HookResult, dir string) {
+	for i := range hooks {
+		h := &hooks[i]
+		if g.checkCancelled() != nil {
+			break
+		}
+
+		logrus.Debug(fmt.Sprintf("running hook, command = '%v'", h.Command))
+
+		output := &hookOutput{emit: func(line string) {
+			g.emit(Event{Type: EventHookOutput, Hook: h.Name, Message: line})
+		}}
+
+		ctx, cancel := context.WithTimeout(g.ctx, hookTimeout)
+		cmd := exec.CommandContext(ctx, h.Command[0], h.Command[1:]...)
+		cmd.Dir = dir
+		cmd.Stdout = output
+		cmd.Stderr = output
+		err := cmd.Run()
+		cancel()
+
+		h.Output = output.flush()
+		if err == nil {
+			h.Status = HookStatusSucceeded
+			continue
+		}
+
+		h.Status = HookStatusFailed
+		h.Error = err.Error()
+		h.ExitCode = -1
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			h.ExitCode = exitErr.ExitCode()
+		}
+
+		if err := g.checkCancelled(); err != nil {
+			h.Error = err.Error()
+		} else if ctx.Err() == context.DeadlineExceeded {
+			h.Error = fmt.Sprintf(
+				util.Msg("timed out after %v"), hookTimeout)
+		}
+
+		break
+	}
+}
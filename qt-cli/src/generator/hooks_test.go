@@ -0,0 +1,99 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"qtcli/common"
+	"qtcli/util"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+const hooksTestTemplate = `
+meta:
+  type: file
+files:
+  - in: file.txt
+    out: '{{.name}}.txt'
+hooks:
+  post:
+    - name: first
+      run: go env GOOS
+    - run: go bogus-{{.name}}
+      when: '{{.fail}}'
+    - name: never
+      run: go env GOARCH
+      when: '{{not .fail}}'
+`
+
+func TestGenerator_Hooks(t *testing.T) {
+	cases := []struct {
+		fail     bool
+		dryRun   bool
+		noHooks  bool
+		expected []HookStatus
+	}{
+		{false, false, false,
+			[]HookStatus{HookStatusSucceeded, HookStatusSucceeded}},
+		{true, false, false,
+			[]HookStatus{HookStatusSucceeded, HookStatusFailed}},
+		{false, true, false,
+			[]HookStatus{HookStatusSkipped, HookStatusSkipped}},
+		{false, false, true,
+			[]HookStatus{HookStatusSkipped, HookStatusSkipped}},
+	}
+
+	env := &Env{
+		FS: fstest.MapFS{
+			"hooked/templates.yml": {Data: []byte(hooksTestTemplate)},
+			"hooked/file.txt":      {Data: []byte("contents")},
+		},
+		TemplateFileName: common.TemplateFileName,
+	}
+
+	for _, tc := range cases {
+		testname := fmt.Sprintf("|%v|%v|%v|", tc.fail, tc.dryRun, tc.noHooks)
+		t.Run(testname, func(t *testing.T) {
+			dir := t.TempDir()
+			preset := common.NewPresetData(
+				"hooked", "hooked", util.StringAnyMap{"fail": tc.fail})
+
+			result := NewGenerator("myfile").
+				Env(env).
+				WorkingDir(filepath.ToSlash(dir)).
+				Preset(preset).
+				DryRun(tc.dryRun).
+				NoHooks(tc.noHooks).
+				Render()
+			require.True(t, result.Success, result.Error.Message)
+
+			hooks := result.Data.GetHooks()
+			statuses := []HookStatus{}
+			for _, h := range hooks {
+				statuses = append(statuses, h.Status)
+			}
+
+			require.Equal(t, tc.expected, statuses)
+			require.Equal(t, "first", hooks[0].Name)
+
+			failed, hasFailed := result.Data.GetFailedHook()
+			require.Equal(t, tc.fail, hasFailed)
+			if hasFailed {
+				require.Equal(t, "go bogus-myfile", failed.Name)
+				require.NotZero(t, failed.ExitCode)
+				require.NotEmpty(t, failed.Output)
+			}
+
+			if tc.dryRun {
+				require.NoFileExists(t, filepath.Join(dir, "myfile.txt"))
+			} else {
+				require.FileExists(t, filepath.Join(dir, "myfile.txt"))
+			}
+		})
+	}
+}
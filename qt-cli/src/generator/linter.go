@@ -0,0 +1,627 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"qtcli/common"
+	"qtcli/util"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Lint checks the template definition and the prompt definition of the
+// given template directory, and the template files it refers to, without
+// rendering anything. Issues carry the path of the file, relative to
+// fsys, and the line they were found at.
+func Lint(fsys fs.FS, dir string) common.Issues {
+	l := linter{
+		fs:       fsys,
+		dir:      dir,
+		issues:   common.Issues{},
+		known:    map[string]bool{"name": true},
+		partials: map[string]bool{},
+	}
+
+	l.run()
+	return l.issues
+}
+
+type linter struct {
+	fs       fs.FS
+	dir      string
+	issues   common.Issues
+	known    map[string]bool // steps, consts and fields
+	partials map[string]bool
+}
+
+type lintIdent struct {
+	name string
+	pos  parse.Pos
+}
+
+var (
+	yamlLineRegex     = regexp.MustCompile(`line (\d+)`)
+	templateLineRegex = regexp.MustCompile(`^template: [^:]*:(\d+):`)
+)
+
+func (l *linter) run() {
+	templatePath := path.Join(l.dir, common.TemplateFileName)
+	templateFile, err := common.OpenTemplateFile(l.fs, templatePath)
+	if err != nil {
+		l.addError(templatePath, lineOfYamlError(err), err.Error())
+		return
+	}
+
+	templateNode, err := readYamlNode(l.fs, templatePath)
+	if err != nil {
+		l.addError(templatePath, lineOfYamlError(err), err.Error())
+		return
+	}
+
+	typeName := strings.ToLower(strings.TrimSpace(templateFile.GetTypeName()))
+	if !templateFile.IsAbstract() && typeName !=
+		common.TargetTypeToString(templateFile.GetTargetType()) {
+		l.addError(templatePath, yamlLine(templateNode, "meta", "type"),
+			fmt.Sprintf("meta.type: invalid type '%v'", typeName))
+	}
+
+	partials, err := readPartials(l.fs, l.dir)
+	if err == nil {
+		for name := range partials {
+			l.partials[name] = true
+		}
+	}
+
+	l.lintPrompt()
+	l.lintFields(templatePath, templateNode, templateFile.GetFields())
+	l.lintFiles(templatePath, templateNode)
+	l.lintHooks(templatePath, templateNode)
+}
+
+func (l *linter) lintPrompt() {
+	merged, err := common.OpenPromptFileIn(l.fs, l.dir)
+	if err != nil {
+		return
+	}
+
+	for _, e := range merged.GetContents().Consts {
+		for name := range e {
+			l.known[name] = true
+		}
+	}
+
+	allSteps := merged.GetContents().Steps
+	for _, step := range allSteps {
+		l.known[step.Id] = true
+	}
+
+	// only the prompt definition of the directory itself is checked,
+	// the inherited steps are checked with the templates they come from
+	promptPath := path.Join(l.dir, common.PromptFileName)
+	if !util.EntryExistsFS(l.fs, promptPath) {
+		return
+	}
+
+	node, err := readYamlNode(l.fs, promptPath)
+	if err != nil {
+		l.addError(promptPath, lineOfYamlError(err), err.Error())
+		return
+	}
+
+	own := common.PromptFileContents{}
+	if err := node.Decode(&own); err != nil {
+		l.addError(promptPath, lineOfYamlError(err), err.Error())
+		return
+	}
+
+	seen := map[string]bool{}
+
+	for i, step := range own.Steps {
+		where := fmt.Sprintf("steps[%d]", i)
+		line := yamlLine(node, "steps", i)
+
+		if len(step.Id) == 0 {
+			l.addError(promptPath, line, where+": id is missing")
+			continue
+		}
+
+		if seen[step.Id] {
+			l.addError(promptPath, yamlLine(node, "steps", i, "id"),
+				fmt.Sprintf("%s: duplicate id '%s'", where, step.Id))
+		}
+
+		seen[step.Id] = true
+
+		switch strings.ToLower(step.CompType) {
+		case "picker", "choices":
+			if len(step.Items) == 0 {
+				l.addError(promptPath, line,
+					fmt.Sprintf("%s: '%s' has no items", where, step.Id))
+			}
+
+		case "input":
+			if _, err := common.NewTagFromRules(step.Rules); err != nil {
+				l.addError(promptPath, yamlLine(node, "steps", i, "rules"),
+					fmt.Sprintf("%s: %v", where, err))
+			}
+
+		case "confirm":
+
+		default:
+			l.addError(promptPath, yamlLine(node, "steps", i, "type"),
+				fmt.Sprintf("%s: %s = '%v'",
+					where, common.OptionInvalidType, step.CompType))
+			continue
+		}
+
+		if step.DefaultValue != nil {
+			_, issues := common.ValidateOptions(
+				[]common.PromptStep{step},
+				util.StringAnyMap{step.Id: step.DefaultValue})
+			for _, issue := range issues {
+				l.addError(promptPath, yamlLine(node, "steps", i, "default"),
+					fmt.Sprintf("%s.default: %s", where, issue.Message))
+			}
+		}
+
+		// a step can only depend on the steps asked before
+		earlier := map[string]bool{"name": true}
+		for _, e := range merged.GetContents().Consts {
+			for name := range e {
+				earlier[name] = true
+			}
+		}
+
+		for _, s := range allSteps {
+			if s.Id == step.Id {
+				break
+			}
+
+			earlier[s.Id] = true
+		}
+
+		l.lintExpr(promptPath, yamlValueNode(node, "steps", i, "when"),
+			where+".when", step.When, earlier, l.known)
+	}
+
+	l.lintMigrations(promptPath, node, own.Migrations, allSteps)
+}
+
+// lintMigrations checks that each migration renames an option to a step,
+// or removes one, and that no step of the template would be migrated
+func (l *linter) lintMigrations(promptPath string, node *yaml.Node,
+	migrations []common.PromptMigration, steps []common.PromptStep) {
+	isStep := func(id string) bool {
+		return slices.ContainsFunc(steps, func(s common.PromptStep) bool {
+			return s.Id == id
+		})
+	}
+
+	for i, m := range migrations {
+		where := fmt.Sprintf("migrations[%d]", i)
+		line := yamlLine(node, "migrations", i)
+
+		switch {
+		case len(m.Rename) != 0 && len(m.Remove) != 0:
+			l.addError(promptPath, line,
+				where+": 'rename' and 'remove' cannot be used together")
+
+		case len(m.Rename) != 0:
+			if len(m.To) == 0 {
+				l.addError(promptPath, line, where+": 'to' is missing")
+			} else if !isStep(m.To) {
+				l.addError(promptPath, yamlLine(node, "migrations", i, "to"),
+					fmt.Sprintf("%s: '%s' is not a step", where, m.To))
+			}
+
+			if isStep(m.Rename) {
+				l.addError(promptPath, yamlLine(node, "migrations", i, "rename"),
+					fmt.Sprintf("%s: '%s' is still a step", where, m.Rename))
+			}
+
+		case len(m.Remove) != 0:
+			if isStep(m.Remove) {
+				l.addError(promptPath, yamlLine(node, "migrations", i, "remove"),
+					fmt.Sprintf("%s: '%s' is still a step", where, m.Remove))
+			}
+
+		default:
+			l.addError(promptPath, line,
+				where+": 'rename' or 'remove' is missing")
+		}
+	}
+}
+
+func (l *linter) lintFields(
+	filePath string, node *yaml.Node, fields []util.StringAnyMap) {
+	defined := map[string]bool{}
+	for name := range l.known {
+		defined[name] = true
+	}
+
+	all := map[string]bool{}
+	for _, field := range fields {
+		for name := range field {
+			all[name] = true
+		}
+	}
+
+	for _, field := range fields {
+		for _, name := range slices.Sorted(maps.Keys(field)) {
+			expr, ok := field[name].(string)
+			if !ok {
+				continue
+			}
+
+			l.lintExpr(filePath, findFieldNode(node, name),
+				"fields."+name, expr, defined, all)
+		}
+
+		for name := range field {
+			defined[name] = true
+		}
+	}
+
+	for name := range all {
+		l.known[name] = true
+	}
+}
+
+func (l *linter) lintFiles(filePath string, node *yaml.Node) {
+	own := common.TemplateFileContents{}
+	if err := node.Decode(&own); err != nil {
+		return
+	}
+
+	for i, item := range own.Files {
+		where := fmt.Sprintf("files[%d]", i)
+
+		l.lintExpr(filePath, yamlValueNode(node, "files", i, "out"),
+			where+".out", item.Out, l.known, l.known)
+		l.lintExpr(filePath, yamlValueNode(node, "files", i, "when"),
+			where+".when", item.When, l.known, l.known)
+
+		if len(item.In) == 0 {
+			l.addError(filePath, yamlLine(node, "files", i),
+				where+": 'in' is missing")
+			continue
+		}
+
+		inputRel := path.Join(l.dir, item.In)
+		if strings.HasPrefix(item.In, "@/") {
+			inputRel = item.In[2:]
+		}
+
+		if !util.EntryExistsFS(l.fs, inputRel) {
+			l.addError(filePath, yamlLine(node, "files", i, "in"),
+				fmt.Sprintf("%s.in: file not found, %s", where, inputRel))
+			continue
+		}
+
+		if !item.Bypass {
+			l.lintContents(inputRel)
+		}
+	}
+}
+
+func (l *linter) lintHooks(filePath string, node *yaml.Node) {
+	own := common.TemplateFileContents{}
+	if err := node.Decode(&own); err != nil {
+		return
+	}
+
+	for i, hook := range own.Hooks.Post {
+		where := fmt.Sprintf("hooks.post[%d]", i)
+
+		if len(strings.TrimSpace(hook.Run)) == 0 {
+			l.addError(filePath, yamlLine(node, "hooks", "post", i),
+				where+": 'run' is missing")
+		}
+
+		l.lintExpr(filePath, yamlValueNode(node, "hooks", "post", i, "run"),
+			where+".run", hook.Run, l.known, l.known)
+		l.lintExpr(filePath, yamlValueNode(node, "hooks", "post", i, "when"),
+			where+".when", hook.When, l.known, l.known)
+	}
+}
+
+// lintExpr checks an expression written in a YAML value. Identifiers
+// must be in 'defined'; the ones only in 'later' are reported as used
+// before being defined.
+func (l *linter) lintExpr(filePath string, node *yaml.Node,
+	where, expr string, defined, later map[string]bool) {
+	if len(strings.TrimSpace(expr)) == 0 {
+		return
+	}
+
+	line := 0
+	if node != nil {
+		line = node.Line
+		if node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+			line++
+		}
+	}
+
+	idents, err := l.parse(expr)
+	if err != nil {
+		l.addError(filePath, offsetLine(line, lineOfTemplateError(err)),
+			fmt.Sprintf("%s: %v", where, err))
+		return
+	}
+
+	for _, id := range idents {
+		if defined[id.name] {
+			continue
+		}
+
+		at := offsetLine(line, lineAt(expr, id.pos))
+		if later[id.name] {
+			l.addError(filePath, at, fmt.Sprintf(
+				"%s: '%s' is used before it is defined", where, id.name))
+		} else {
+			l.addError(filePath, at, fmt.Sprintf(
+				"%s: unknown identifier '%s'", where, id.name))
+		}
+	}
+}
+
+// lintContents checks a template file. Unknown identifiers are only
+// warnings here, since templates commonly test for optional values.
+func (l *linter) lintContents(filePath string) {
+	raw, err := util.ReadAllFromFS(l.fs, filePath)
+	if err != nil {
+		l.addError(filePath, 0, err.Error())
+		return
+	}
+
+	text := string(raw)
+	idents, err := l.parse(text)
+	if err != nil {
+		l.addError(filePath, lineOfTemplateError(err), err.Error())
+		return
+	}
+
+	for _, id := range idents {
+		if !l.known[id.name] && id.name != "fileName" {
+			l.issues = append(l.issues, common.Issue{
+				Level:   common.IssueLevelWarning,
+				Field:   filePath,
+				Line:    lineAt(text, id.pos),
+				Message: fmt.Sprintf("unknown identifier '%s'", id.name),
+			})
+		}
+	}
+}
+
+// parse parses a template and returns the identifiers it reads from
+// the data, e.g. 'name' for '{{ .name }}' or '{{ $.name }}'. Calls of
+// undefined partials are reported as errors.
+func (l *linter) parse(text string) ([]lintIdent, error) {
+	tmpl, err := template.New("t").Funcs(getApi()).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	idents := []lintIdent{}
+	calls := []*parse.TemplateNode{}
+
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil && t.Tree.Root != nil {
+			collectIdents(t.Tree.Root, true, &idents, &calls)
+		}
+	}
+
+	for _, call := range calls {
+		if !l.partials[call.Name] && tmpl.Lookup(call.Name) == nil {
+			return nil, fmt.Errorf("template: t:%d: no such partial \"%s\"",
+				lineAt(text, call.Pos), call.Name)
+		}
+	}
+
+	return idents, nil
+}
+
+func collectIdents(node parse.Node, rootDot bool,
+	idents *[]lintIdent, calls *[]*parse.TemplateNode) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+
+		for _, child := range n.Nodes {
+			collectIdents(child, rootDot, idents, calls)
+		}
+
+	case *parse.ActionNode:
+		collectIdents(n.Pipe, rootDot, idents, calls)
+
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+
+		for _, cmd := range n.Cmds {
+			collectIdents(cmd, rootDot, idents, calls)
+		}
+
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			collectIdents(arg, rootDot, idents, calls)
+		}
+
+	case *parse.ChainNode:
+		collectIdents(n.Node, rootDot, idents, calls)
+
+	case *parse.FieldNode:
+		if rootDot {
+			*idents = append(*idents, lintIdent{n.Ident[0], n.Pos})
+		}
+
+	case *parse.VariableNode:
+		if n.Ident[0] == "$" && len(n.Ident) > 1 {
+			*idents = append(*idents, lintIdent{n.Ident[1], n.Pos})
+		}
+
+	case *parse.IfNode:
+		collectIdents(n.Pipe, rootDot, idents, calls)
+		collectIdents(n.List, rootDot, idents, calls)
+		collectIdents(n.ElseList, rootDot, idents, calls)
+
+	case *parse.RangeNode:
+		collectIdents(n.Pipe, rootDot, idents, calls)
+		collectIdents(n.List, false, idents, calls)
+		collectIdents(n.ElseList, rootDot, idents, calls)
+
+	case *parse.WithNode:
+		collectIdents(n.Pipe, rootDot, idents, calls)
+		collectIdents(n.List, false, idents, calls)
+		collectIdents(n.ElseList, rootDot, idents, calls)
+
+	case *parse.TemplateNode:
+		*calls = append(*calls, n)
+		collectIdents(n.Pipe, rootDot, idents, calls)
+	}
+}
+
+func (l *linter) addError(filePath string, line int, message string) {
+	l.issues = append(l.issues, common.Issue{
+		Level:   common.IssueLevelError,
+		Field:   filePath,
+		Line:    line,
+		Message: message,
+	})
+}
+
+// helpers
+func readYamlNode(fsys fs.FS, filePath string) (*yaml.Node, error) {
+	raw, err := util.ReadAllFromFS(fsys, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(raw, &node); err != nil {
+		return nil, err
+	}
+
+	return &node, nil
+}
+
+// yamlValueNode follows the given mapping keys and sequence indices
+func yamlValueNode(node *yaml.Node, keys ...any) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+
+	if node.Kind == yaml.DocumentNode && len(node.Content) != 0 {
+		node = node.Content[0]
+	}
+
+	for _, key := range keys {
+		var next *yaml.Node
+
+		switch k := key.(type) {
+		case string:
+			if node.Kind != yaml.MappingNode {
+				return nil
+			}
+
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == k {
+					next = node.Content[i+1]
+					break
+				}
+			}
+
+		case int:
+			if node.Kind == yaml.SequenceNode && k < len(node.Content) {
+				next = node.Content[k]
+			}
+		}
+
+		if next == nil {
+			return nil
+		}
+
+		node = next
+	}
+
+	return node
+}
+
+// yamlLine returns the line of the value at the given keys, or of the
+// closest parent found
+func yamlLine(node *yaml.Node, keys ...any) int {
+	for n := len(keys); n >= 0; n-- {
+		if v := yamlValueNode(node, keys[:n]...); v != nil {
+			return v.Line
+		}
+	}
+
+	return 0
+}
+
+func findFieldNode(node *yaml.Node, name string) *yaml.Node {
+	fields := yamlValueNode(node, "fields")
+	if fields == nil {
+		return nil
+	}
+
+	for i := range fields.Content {
+		if v := yamlValueNode(fields, i, name); v != nil {
+			return v
+		}
+	}
+
+	return nil
+}
+
+func lineAt(text string, pos parse.Pos) int {
+	if int(pos) > len(text) {
+		pos = parse.Pos(len(text))
+	}
+
+	return 1 + strings.Count(text[:pos], "\n")
+}
+
+func offsetLine(base, line int) int {
+	if base == 0 {
+		return 0
+	}
+
+	if line == 0 {
+		return base
+	}
+
+	return base + line - 1
+}
+
+func lineOfYamlError(err error) int {
+	return atoiMatch(yamlLineRegex, err.Error())
+}
+
+func lineOfTemplateError(err error) int {
+	return atoiMatch(templateLineRegex, err.Error())
+}
+
+func atoiMatch(r *regexp.Regexp, s string) int {
+	m := r.FindStringSubmatch(s)
+	if m == nil {
+		return 0
+	}
+
+	n, _ := strconv.Atoi(m[1])
+	return n
+}
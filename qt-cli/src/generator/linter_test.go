@@ -0,0 +1,135 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"qtcli/common"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	fsys := fstest.MapFS{
+		"t/templates.yml": {Data: []byte(`meta:
+  type: file
+files:
+  - in: a.txt
+    when: '{{ .nope }}'
+  - in: missing.txt
+fields:
+  - first: '{{ .second }}'
+  - second: |
+      {{ .name }}
+      {{ .unknownThing }}
+hooks:
+  post:
+    - run: 'echo {{ template "nopartial" }}'
+`)},
+		"t/prompt.yml": {Data: []byte(`steps:
+  - id: color
+    type: picker
+    default: purple
+    when: '{{ .later }}'
+    items:
+      - text: red
+  - id: later
+    type: confirm
+`)},
+		"t/a.txt": {Data: []byte("x\n{{ .first }} {{ .optional }}\n{{ if }}\n")},
+	}
+
+	expected := []string{
+		"error|t/prompt.yml|4|steps[0].default",
+		"error|t/prompt.yml|5|'later' is used before it is defined",
+		"error|t/templates.yml|8|'second' is used before it is defined",
+		"error|t/templates.yml|11|unknown identifier 'unknownThing'",
+		"error|t/templates.yml|5|unknown identifier 'nope'",
+		"error|t/a.txt|3|missing value for if",
+		"error|t/templates.yml|6|file not found",
+		"error|t/templates.yml|14|no such partial \"nopartial\"",
+	}
+
+	issues := Lint(fsys, "t")
+	require.Len(t, issues, len(expected))
+
+	for i, issue := range issues {
+		parts := strings.SplitN(expected[i], "|", 4)
+		require.Equal(t, parts[0], string(issue.Level))
+		require.Equal(t, parts[1], issue.Field)
+		require.Equal(t, parts[2], fmt.Sprint(issue.Line))
+		require.Contains(t, issue.Message, parts[3])
+	}
+
+	fsys["t/a.txt"] = &fstest.MapFile{Data: []byte("{{ .optional }}")}
+	issues = Lint(fsys, "t")
+	require.Contains(t, issues, common.Issue{
+		Level:   common.IssueLevelWarning,
+		Field:   "t/a.txt",
+		Line:    1,
+		Message: "unknown identifier 'optional'",
+	})
+}
+
+func TestLint_Migrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"t/templates.yml": {Data: []byte("meta:\n  type: file\n")},
+		"t/prompt.yml": {Data: []byte(`steps:
+  - id: color
+    type: confirm
+migrations:
+  - rename: colour
+    to: color
+  - rename: shade
+  - rename: color
+    to: hue
+  - remove: color
+  - to: color
+`)},
+	}
+
+	expected := []string{
+		"7|migrations[1]: 'to' is missing",
+		"9|migrations[2]: 'hue' is not a step",
+		"8|migrations[2]: 'color' is still a step",
+		"10|migrations[3]: 'color' is still a step",
+		"11|migrations[4]: 'rename' or 'remove' is missing",
+	}
+
+	issues := Lint(fsys, "t")
+	require.Len(t, issues, len(expected))
+
+	for i, issue := range issues {
+		parts := strings.SplitN(expected[i], "|", 2)
+		require.Equal(t, common.IssueLevelError, issue.Level)
+		require.Equal(t, parts[0], fmt.Sprint(issue.Line))
+		require.Equal(t, parts[1], issue.Message)
+	}
+}
+
+func TestLint_BuiltInTemplates(t *testing.T) {
+	fs.WalkDir(common.TemplatesFS, ".",
+		func(walkingPath string, d fs.DirEntry, err error) error {
+			if err != nil || !d.IsDir() {
+				return err
+			}
+
+			templatePath := path.Join(walkingPath, common.TemplateFileName)
+			if _, err := fs.Stat(common.TemplatesFS, templatePath); err != nil {
+				return nil
+			}
+
+			t.Run(walkingPath, func(t *testing.T) {
+				issues := Lint(common.TemplatesFS, walkingPath)
+				require.False(t, issues.HasError(), issues.String())
+			})
+
+			return nil
+		})
+}
@@ -0,0 +1,199 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"qtcli/util"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ManifestDirName  = ".qtcli"
+	ManifestFileName = "manifest.yml"
+
+	manifestVersion = 1
+)
+
+// Manifest records how a project was generated, so that it can be told
+// later which files were changed by hand, and what to upgrade. Source
+// identifies the template files it was rendered from, which are kept in
+// the template store of the user.
+type Manifest struct {
+	Version      int               `yaml:"version" json:"version"`
+	QtcliVersion string            `yaml:"qtcli" json:"qtcli"`
+	Preset       string            `yaml:"preset" json:"preset"`
+	TemplateDir  string            `yaml:"template" json:"template"`
+	Revision     string            `yaml:"revision" json:"revision"`
+	Source       string            `yaml:"source" json:"source"`
+	Data         util.StringAnyMap `yaml:"data" json:"data"`
+	Files        []ManifestFile    `yaml:"files" json:"files"`
+}
+
+type ManifestFile struct {
+	Path string `yaml:"path" json:"path"` // relative to the project
+	Hash string `yaml:"sha256" json:"sha256"`
+}
+
+// ManifestFileState tells how a generated file compares to its hash
+type ManifestFileState string
+
+const (
+	ManifestFileUnchanged ManifestFileState = "unchanged"
+	ManifestFileModified  ManifestFileState = "modified"
+	ManifestFileMissing   ManifestFileState = "missing"
+)
+
+type ManifestFileStatus struct {
+	Path  string            `json:"path"`
+	State ManifestFileState `json:"state"`
+}
+
+// ManifestPath returns where the manifest of a project is
+func ManifestPath(projectDir string) string {
+	return path.Join(projectDir, ManifestDirName, ManifestFileName)
+}
+
+// FindManifest returns the directory of the project containing dir,
+// that is the closest one having a manifest
+func FindManifest(dir string) (string, bool) {
+	for {
+		if util.EntryExists(ManifestPath(dir)) {
+			return dir, true
+		}
+
+		parent := path.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+
+		dir = parent
+	}
+}
+
+func ReadManifest(projectDir string) (*Manifest, error) {
+	raw, err := os.ReadFile(ManifestPath(projectDir))
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{}
+	if err := yaml.Unmarshal(raw, m); err != nil {
+		return nil, fmt.Errorf("%s: %w", ManifestPath(projectDir), err)
+	}
+
+	if m.Version > manifestVersion {
+		return nil, fmt.Errorf(util.Msg(
+			"the manifest was written by a newer qtcli, version = %d"),
+			m.Version)
+	}
+
+	return m, nil
+}
+
+func (m *Manifest) ToYaml() ([]byte, error) {
+	return yaml.Marshal(m)
+}
+
+// Status compares the generated files with what is on disk now
+func (m *Manifest) Status(projectDir string) ([]ManifestFileStatus, error) {
+	all := []ManifestFileStatus{}
+
+	for _, file := range m.Files {
+		status := ManifestFileStatus{
+			Path:  file.Path,
+			State: ManifestFileUnchanged,
+		}
+
+		contents, err := os.ReadFile(path.Join(projectDir, file.Path))
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			status.State = ManifestFileMissing
+		case err != nil:
+			return nil, err
+		case HashContents(contents) != file.Hash:
+			status.State = ManifestFileModified
+		}
+
+		all = append(all, status)
+	}
+
+	return all, nil
+}
+
+func HashContents(contents []byte) string {
+	sum := sha256.Sum256(contents)
+	return hex.EncodeToString(sum[:])
+}
+
+// addManifest schedules the manifest of the project to be written with
+// the generated files
+func (g *Generator) addManifest(tx *transaction, result *ResultData) error {
+	m, err := g.newManifest(result)
+	if err != nil {
+		return err
+	}
+
+	contents, err := m.ToYaml()
+	if err != nil {
+		return err
+	}
+
+	tx.Add(ManifestPath(result.outputDirAbs), contents, "")
+	return nil
+}
+
+// newManifest describes the files of the result which are written
+func (g *Generator) newManifest(result *ResultData) (*Manifest, error) {
+	template, err := g.readTemplateFile()
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{
+		Version:      manifestVersion,
+		QtcliVersion: g.env.Version,
+		Preset:       g.preset.GetName(),
+		TemplateDir:  g.preset.GetTemplateDir(),
+		Revision:     template.GetVersion(),
+		Data:         maps.Clone(g.context.data),
+		Files:        []ManifestFile{},
+	}
+
+	if g.sources != nil {
+		id, files, err := g.sources.source()
+		if err != nil {
+			return nil, err
+		}
+
+		m.Source = id
+		if len(g.env.StoreDir) != 0 {
+			err := saveTemplateSource(g.env.StoreDir, id, files)
+			if err != nil {
+				g.warn("cannot keep the template files for upgrades: %v", err)
+			}
+		}
+	}
+
+	for _, item := range result.items {
+		if item.action == FileActionSkipped {
+			continue
+		}
+
+		m.Files = append(m.Files, ManifestFile{
+			Path: item.outputFileRel,
+			Hash: HashContents(item.contents),
+		})
+	}
+
+	return m, nil
+}
@@ -0,0 +1,68 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"qtcli/common"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerator_Manifest(t *testing.T) {
+	workingDir := filepath.ToSlash(t.TempDir())
+	env := &Env{
+		FS:               common.TemplatesFS,
+		FileTypesBaseDir: "types",
+		TemplateFileName: common.TemplateFileName,
+		Version:          "1.2.3",
+	}
+
+	preset := common.NewPresetData("@projects/cpp/console",
+		"projects/cpp/console", map[string]any{})
+
+	result := NewGenerator("myapp").
+		Env(env).
+		WorkingDir(workingDir).
+		Preset(preset).
+		Render()
+	require.True(t, result.Success, result.Error.Message)
+
+	projectDir := path.Join(workingDir, "myapp")
+	found, ok := FindManifest(path.Join(projectDir, "sub", "dir"))
+	require.True(t, ok)
+	require.Equal(t, projectDir, found)
+
+	m, err := ReadManifest(projectDir)
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3", m.QtcliVersion)
+	assert.Equal(t, "@projects/cpp/console", m.Preset)
+	assert.Equal(t, "projects/cpp/console", m.TemplateDir)
+	assert.Equal(t, "myapp", m.Data["name"])
+	assert.NotContains(t, m.Data, "fileName")
+	assert.Len(t, m.Files, len(result.Data.GetFiles()))
+
+	require.NoError(t, os.WriteFile(
+		path.Join(projectDir, "main.cpp"), []byte("changed\n"), 0644))
+	require.NoError(t, os.Remove(path.Join(projectDir, "CMakeLists.txt")))
+
+	status, err := m.Status(projectDir)
+	require.NoError(t, err)
+
+	states := map[string]ManifestFileState{}
+	for _, s := range status {
+		states[s.Path] = s.State
+	}
+
+	assert.Equal(t, ManifestFileModified, states["main.cpp"])
+	assert.Equal(t, ManifestFileMissing, states["CMakeLists.txt"])
+	assert.Equal(t, ManifestFileUnchanged, states[".gitignore"])
+
+	_, ok = FindManifest(workingDir)
+	assert.False(t, ok)
+}
@@ -6,7 +6,11 @@ package generator
 import (
 	"fmt"
 	"io"
+	"path"
 	"qtcli/common"
+	"qtcli/util"
+	"slices"
+	"strings"
 	"text/tabwriter"
 )
 
@@ -14,6 +18,9 @@ type Result struct {
 	Success bool
 	Data    ResultData
 	Error   common.Error
+
+	// Committed is true only if all the files have been written to disk
+	Committed bool
 }
 
 func NewOkayResult(data ResultData) *Result {
@@ -36,8 +43,15 @@ func NewErrorResultFrom(err error) *Result {
 	})
 }
 
+func newNothingWrittenResult(err error) *Result {
+	return NewErrorResult(common.Error{
+		Message: fmt.Sprintf("%v (%s)", err, common.GeneratorNothingWritten),
+	})
+}
+
 type ResultData struct {
 	items        []ResultItem
+	hooks        []HookResult
 	workingDir   string
 	outputDirAbs string
 }
@@ -47,6 +61,18 @@ type ResultItem struct {
 	inputFileRel  string // relative to env.FS
 	outputFileRel string // relative to outputDirAbs
 	outputFileAbs string
+	backupFileAbs string
+	action        FileAction
+	contents      []byte
+	diff          string // against the existing file, only on dry runs
+}
+
+type ResultFile struct {
+	Output   string
+	Action   FileAction
+	Backup   string
+	Contents string
+	Diff     string
 }
 
 func (r *ResultData) Print(output io.Writer) {
@@ -54,22 +80,94 @@ func (r *ResultData) Print(output io.Writer) {
 
 	for _, item := range r.items {
 		fmt.Fprintf(
-			w, "%s\t->\t%s\n", item.templateItem.In, item.outputFileRel)
+			w, "%s\t->\t%s\t(%s)\n",
+			item.templateItem.In, item.outputFileRel, item.action)
+	}
+
+	for _, hook := range r.hooks {
+		fmt.Fprintf(w, "%s\t\t\t(%s)\n", hook.Name, hook.Status)
 	}
 
 	w.Flush()
 }
 
+// PrintPreview shows what a dry run would write: the diff for existing
+// files, and the whole contents for new ones
+func (r *ResultData) PrintPreview(output io.Writer) {
+	for _, item := range r.items {
+		fmt.Fprintf(output, "==> %s (%s)\n",
+			path.Join(r.outputDirAbs, item.outputFileRel), item.action)
+
+		switch {
+		case item.action == FileActionSkipped:
+		case item.action == FileActionCreated:
+			fmt.Fprintln(output, strings.TrimRight(string(item.contents), "\n"))
+		case len(item.diff) == 0:
+			fmt.Fprintln(output, util.Msg("(no changes)"))
+		default:
+			fmt.Fprint(output, item.diff)
+		}
+
+		fmt.Fprintln(output)
+	}
+
+	for _, hook := range r.hooks {
+		fmt.Fprintf(output, "==> %s (%s)\n\n",
+			strings.Join(hook.Command, " "), util.Msg("hook, not run"))
+	}
+}
+
+func (r *ResultData) GetHooks() []HookResult {
+	return slices.Clone(r.hooks)
+}
+
+// GetFailedHook returns the hook which stopped the others, if any
+func (r *ResultData) GetFailedHook() (HookResult, bool) {
+	for _, hook := range r.hooks {
+		if hook.Status == HookStatusFailed {
+			return hook, true
+		}
+	}
+
+	return HookResult{}, false
+}
+
+func (r *ResultData) GetWorkingDir() string {
+	return r.workingDir
+}
+
 func (r *ResultData) GetOutputDirAbs() string {
 	return r.outputDirAbs
 }
 
+// GetOutputFilesRel returns the files generated from the template, not
+// the project files updated to include them
 func (r *ResultData) GetOutputFilesRel() []string {
 	all := []string{}
 
 	for _, item := range r.items {
+		if item.action == FileActionUpdated {
+			continue
+		}
+
 		all = append(all, item.outputFileRel)
 	}
 
 	return all
 }
+
+func (r *ResultData) GetFiles() []ResultFile {
+	all := []ResultFile{}
+
+	for _, item := range r.items {
+		all = append(all, ResultFile{
+			Output:   item.outputFileRel,
+			Action:   item.action,
+			Backup:   item.backupFileAbs,
+			Contents: string(item.contents),
+			Diff:     item.diff,
+		})
+	}
+
+	return all
+}
@@ -0,0 +1,145 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"qtcli/util"
+	"slices"
+	"sync"
+)
+
+// recordingFS remembers the files read through it, which are the files
+// of the templates a project is rendered from
+type recordingFS struct {
+	fsys  fs.FS
+	mutex sync.Mutex
+	files map[string]bool
+}
+
+func newRecordingFS(fsys fs.FS) *recordingFS {
+	return &recordingFS{fsys: fsys, files: map[string]bool{}}
+}
+
+func (r *recordingFS) Open(name string) (fs.File, error) {
+	f, err := r.fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if info, err := f.Stat(); err == nil && info.Mode().IsRegular() {
+		r.record(name)
+	}
+
+	return f, nil
+}
+
+func (r *recordingFS) ReadFile(name string) ([]byte, error) {
+	contents, err := fs.ReadFile(r.fsys, name)
+	if err == nil {
+		r.record(name)
+	}
+
+	return contents, err
+}
+
+// ReadDir and Stat are passed on, since the file system may merge
+// directories of several layers
+func (r *recordingFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(r.fsys, name)
+}
+
+func (r *recordingFS) Stat(name string) (fs.FileInfo, error) {
+	return fs.Stat(r.fsys, name)
+}
+
+func (r *recordingFS) record(name string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.files[name] = true
+}
+
+// source returns the files read so far, with their contents, and the id
+// telling them apart from the files of any other revision
+func (r *recordingFS) source() (string, map[string][]byte, error) {
+	r.mutex.Lock()
+	names := slices.Sorted(maps.Keys(r.files))
+	r.mutex.Unlock()
+
+	files := map[string][]byte{}
+	hash := sha256.New()
+
+	for _, name := range names {
+		contents, err := fs.ReadFile(r.fsys, name)
+		if err != nil {
+			return "", nil, err
+		}
+
+		files[name] = contents
+		fmt.Fprintf(hash, "%s\x00%d\x00", name, len(contents))
+		hash.Write(contents)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), files, nil
+}
+
+// saveTemplateSource keeps the given template files in the store, under
+// their id, unless they are already there
+func saveTemplateSource(storeDir, id string, files map[string][]byte) error {
+	dir := filepath.Join(storeDir, id)
+	if util.EntryExists(dir) {
+		return nil
+	}
+
+	if err := os.MkdirAll(storeDir, 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.MkdirTemp(storeDir, id+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	defer os.RemoveAll(tmp)
+
+	for name, contents := range files {
+		filePath := filepath.Join(tmp, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(filePath, contents, 0644); err != nil {
+			return err
+		}
+	}
+
+	// another process may have kept the same files meanwhile
+	if err := os.Rename(tmp, dir); err != nil && !util.EntryExists(dir) {
+		return err
+	}
+
+	return nil
+}
+
+// openTemplateSource returns the template files of the given id, if they
+// are in the store
+func openTemplateSource(storeDir, id string) (fs.FS, bool) {
+	if len(storeDir) == 0 || len(id) == 0 {
+		return nil, false
+	}
+
+	dir := filepath.Join(storeDir, id)
+	if !util.EntryExists(dir) {
+		return nil, false
+	}
+
+	return os.DirFS(dir), true
+}
@@ -0,0 +1,231 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+)
+
+var errRollbackFailed = errors.New(
+	"cannot undo the changes, some files may have been left behind")
+
+// transaction writes a set of files all at once. Every file is first
+// written to a temporary file next to its destination; only when all of
+// them are written, they are moved into place. If anything fails on the
+// way, all changes are undone, including the directories created.
+type transaction struct {
+	entries     []txEntry
+	createdDirs []string
+	committed   []int
+}
+
+type txEntry struct {
+	target   string
+	contents []byte
+	backup   string // if set, the existing target is moved here
+	remove   bool   // the target is removed instead of written
+	tempFile string
+	asideAt  string // where the existing target was moved
+}
+
+func newTransaction() *transaction {
+	return &transaction{}
+}
+
+// Add schedules a write. If backup is given, the existing target is kept
+// under that path; otherwise an existing target is replaced.
+func (tx *transaction) Add(target string, contents []byte, backup string) {
+	tx.entries = append(tx.entries, txEntry{
+		target:   target,
+		contents: contents,
+		backup:   backup,
+	})
+}
+
+// Remove schedules the removal of an existing target
+func (tx *transaction) Remove(target string) {
+	tx.entries = append(tx.entries, txEntry{
+		target: target,
+		remove: true,
+	})
+}
+
+func (tx *transaction) Commit() error {
+	if err := tx.commit(); err != nil {
+		if rollbackErr := tx.rollback(); rollbackErr != nil {
+			return errors.Join(err, rollbackErr)
+		}
+
+		return err
+	}
+
+	tx.cleanup()
+	return nil
+}
+
+func (tx *transaction) commit() error {
+	// stage
+	for i := range tx.entries {
+		if err := tx.stage(&tx.entries[i]); err != nil {
+			return err
+		}
+	}
+
+	// move into place
+	for i := range tx.entries {
+		e := &tx.entries[i]
+
+		if _, err := os.Lstat(e.target); err == nil {
+			aside := e.backup
+			if len(aside) == 0 {
+				aside = tempPathFor(e.target, "orig")
+			}
+
+			if err := os.Rename(e.target, aside); err != nil {
+				return err
+			}
+
+			e.asideAt = aside
+		}
+
+		if e.remove {
+			tx.committed = append(tx.committed, i)
+			continue
+		}
+
+		if err := os.Rename(e.tempFile, e.target); err != nil {
+			return err
+		}
+
+		e.tempFile = ""
+		tx.committed = append(tx.committed, i)
+	}
+
+	return nil
+}
+
+func (tx *transaction) stage(e *txEntry) error {
+	if e.remove {
+		return nil
+	}
+
+	dir := filepath.Dir(e.target)
+	if err := tx.mkdirAll(dir); err != nil {
+		return err
+	}
+
+	mode := os.FileMode(0666)
+	if stat, err := os.Stat(e.target); err == nil {
+		mode = stat.Mode().Perm()
+	}
+
+	for {
+		candidate := tempPathFor(e.target, "new")
+		f, err := os.OpenFile(
+			candidate, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+		if os.IsExist(err) {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		e.tempFile = candidate
+		_, err = f.Write(e.contents)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+
+		return err
+	}
+}
+
+func (tx *transaction) rollback() error {
+	all := []error{}
+
+	for i := len(tx.committed) - 1; i >= 0; i-- {
+		e := &tx.entries[tx.committed[i]]
+		if err := os.Remove(e.target); err != nil && !os.IsNotExist(err) {
+			all = append(all, err)
+		}
+	}
+
+	for i := range tx.entries {
+		e := &tx.entries[i]
+
+		if len(e.asideAt) != 0 {
+			if err := os.Rename(e.asideAt, e.target); err != nil {
+				all = append(all, err)
+			}
+		}
+
+		if len(e.tempFile) != 0 {
+			os.Remove(e.tempFile)
+		}
+	}
+
+	for i := len(tx.createdDirs) - 1; i >= 0; i-- {
+		os.Remove(tx.createdDirs[i])
+	}
+
+	if len(all) != 0 {
+		return fmt.Errorf("%w: %w", errRollbackFailed, errors.Join(all...))
+	}
+
+	return nil
+}
+
+func (tx *transaction) cleanup() {
+	for _, e := range tx.entries {
+		if len(e.asideAt) != 0 && len(e.backup) == 0 {
+			if err := os.Remove(e.asideAt); err != nil {
+				logrus.Warn("cannot remove temporary file: ", err)
+			}
+		}
+	}
+}
+
+// mkdirAll is like os.MkdirAll, but remembers what it has created
+func (tx *transaction) mkdirAll(dir string) error {
+	missing := []string{}
+
+	for d := dir; ; d = filepath.Dir(d) {
+		stat, err := os.Stat(d)
+		if err == nil {
+			if !stat.IsDir() {
+				return fmt.Errorf("not a directory, %s", d)
+			}
+
+			break
+		}
+
+		missing = append(missing, d)
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+
+	for i := len(missing) - 1; i >= 0; i-- {
+		if err := os.Mkdir(missing[i], os.ModePerm); err != nil {
+			return err
+		}
+
+		tx.createdDirs = append(tx.createdDirs, missing[i])
+	}
+
+	return nil
+}
+
+func tempPathFor(target, tag string) string {
+	dir, base := filepath.Split(target)
+	return filepath.Join(dir, fmt.Sprintf(
+		".%s.qtcli-%s-%08x", base, tag, rand.Uint32()))
+}
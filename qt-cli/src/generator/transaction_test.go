@@ -0,0 +1,74 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransaction_Commit(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.txt")
+	kept := filepath.Join(dir, "kept.txt")
+	require.NoError(t, os.WriteFile(existing, []byte("old"), 0644))
+	require.NoError(t, os.WriteFile(kept, []byte("old"), 0644))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(dir, "gone.txt"), []byte("old"), 0644))
+
+	tx := newTransaction()
+	tx.Add(filepath.Join(dir, "a", "b", "new.txt"), []byte("new"), "")
+	tx.Add(existing, []byte("new"), "")
+	tx.Add(kept, []byte("new"), kept+".bak")
+	tx.Remove(filepath.Join(dir, "gone.txt"))
+	require.NoError(t, tx.Commit())
+
+	ensureContents(t, filepath.Join(dir, "a", "b", "new.txt"), "new")
+	ensureContents(t, existing, "new")
+	ensureContents(t, kept, "new")
+	ensureContents(t, kept+".bak", "old")
+	ensureEntries(t, dir, []string{"a", "existing.txt", "kept.txt", "kept.txt.bak"})
+}
+
+func TestTransaction_Rollback(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.txt")
+	kept := filepath.Join(dir, "kept.txt")
+	blocker := filepath.Join(dir, "blocker")
+	require.NoError(t, os.WriteFile(existing, []byte("old"), 0644))
+	require.NoError(t, os.WriteFile(kept, []byte("old"), 0644))
+	require.NoError(t, os.WriteFile(blocker, []byte("file"), 0644))
+
+	tx := newTransaction()
+	tx.Add(filepath.Join(dir, "a", "b", "new.txt"), []byte("new"), "")
+	tx.Add(existing, []byte("new"), "")
+	tx.Add(kept, []byte("new"), kept+".bak")
+	tx.Add(filepath.Join(blocker, "fails.txt"), []byte("new"), "")
+	require.Error(t, tx.Commit())
+
+	ensureContents(t, existing, "old")
+	ensureContents(t, kept, "old")
+	ensureEntries(t, dir, []string{"blocker", "existing.txt", "kept.txt"})
+}
+
+func ensureContents(t *testing.T, file, expected string) {
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.Equal(t, expected, string(data))
+}
+
+func ensureEntries(t *testing.T, dir string, expected []string) {
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	names := []string{}
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+
+	require.Equal(t, expected, names)
+}
@@ -0,0 +1,394 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"qtcli/common"
+	"qtcli/util"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+)
+
+// UpgradeState tells what an upgrade does to a file of a project
+type UpgradeState string
+
+const (
+	// neither the template nor the file changed, or only the file did
+	UpgradeUnchanged UpgradeState = "unchanged"
+
+	// the template changed and the file did not: it is replaced
+	UpgradeUpdated UpgradeState = "updated"
+
+	// both changed, in different places
+	UpgradeMerged UpgradeState = "merged"
+
+	// both changed the same lines: the file gets conflict markers
+	UpgradeConflict UpgradeState = "conflict"
+
+	// the template has a new file
+	UpgradeAdded UpgradeState = "added"
+
+	// the template dropped a file which was not changed
+	UpgradeRemoved UpgradeState = "removed"
+
+	// the template dropped a file which was changed, or changed a file
+	// which was removed by hand: it is left as it is
+	UpgradeKept UpgradeState = "kept"
+)
+
+type UpgradeFile struct {
+	Path      string       `json:"path"`
+	State     UpgradeState `json:"state"`
+	Conflicts int          `json:"conflicts,omitempty"`
+	Diff      string       `json:"diff,omitempty"` // dry runs only
+}
+
+type UpgradeResult struct {
+	ProjectDir   string        `json:"projectDir"`
+	FromRevision string        `json:"fromRevision"`
+	ToRevision   string        `json:"toRevision"`
+	DryRun       bool          `json:"dryRun"`
+	Files        []UpgradeFile `json:"files"`
+}
+
+// Upgrader applies the changes made to a template since a project was
+// generated from it. The project is rendered twice with the answers
+// recorded in its manifest: from the template files it was generated
+// from, kept in the template store, and from the current ones. What
+// changed between both is merged into the current files.
+type Upgrader struct {
+	env        *Env
+	projectDir string
+	dryRun     bool
+}
+
+func NewUpgrader(projectDir string) *Upgrader {
+	return &Upgrader{projectDir: projectDir}
+}
+
+func (u *Upgrader) Env(env *Env) *Upgrader {
+	u.env = env
+	return u
+}
+
+func (u *Upgrader) DryRun(on bool) *Upgrader {
+	u.dryRun = on
+	return u
+}
+
+func (u *Upgrader) Run() (UpgradeResult, error) {
+	manifest, err := ReadManifest(u.projectDir)
+	if err != nil {
+		return UpgradeResult{}, err
+	}
+
+	options, err := u.currentOptions(manifest)
+	if err != nil {
+		return UpgradeResult{}, err
+	}
+
+	g, rendered, err := u.render(manifest, u.env.FS, options)
+	if err != nil {
+		return UpgradeResult{}, err
+	}
+
+	bases, err := u.renderBase(manifest, g, rendered)
+	if err != nil {
+		return UpgradeResult{}, err
+	}
+
+	template, err := g.readTemplateFile()
+	if err != nil {
+		return UpgradeResult{}, err
+	}
+
+	result := UpgradeResult{
+		ProjectDir:   u.projectDir,
+		FromRevision: manifest.Revision,
+		ToRevision:   template.GetVersion(),
+		DryRun:       u.dryRun,
+		Files:        []UpgradeFile{},
+	}
+
+	tx := newTransaction()
+	paths := []string{}
+	for _, file := range manifest.Files {
+		paths = append(paths, file.Path)
+	}
+
+	for _, item := range rendered.items {
+		if !slices.Contains(paths, item.outputFileRel) {
+			paths = append(paths, item.outputFileRel)
+		}
+	}
+
+	for _, rel := range paths {
+		i := slices.IndexFunc(rendered.items, func(item ResultItem) bool {
+			return item.outputFileRel == rel
+		})
+
+		var theirs []byte
+		if i >= 0 {
+			theirs = rendered.items[i].contents
+		}
+
+		file, err := u.upgradeFile(tx, manifest, rel, theirs, i >= 0, bases)
+		if err != nil {
+			return UpgradeResult{}, err
+		}
+
+		result.Files = append(result.Files, file)
+	}
+
+	if u.dryRun {
+		return result, nil
+	}
+
+	rendered.outputDirAbs = u.projectDir
+	if err := g.addManifest(tx, &rendered); err != nil {
+		return UpgradeResult{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return UpgradeResult{}, err
+	}
+
+	return result, nil
+}
+
+// currentOptions returns the answers of the manifest. Values asked by
+// prompts added to the template since then get their default values.
+func (u *Upgrader) currentOptions(m *Manifest) (util.StringAnyMap, error) {
+	options := util.StringAnyMap{}
+
+	promptFile, err := common.OpenPromptFileIn(u.env.FS, m.TemplateDir)
+	if err == nil {
+		options = promptFile.ExtractDefaults()
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	return util.Merge(options, m.Data), nil
+}
+
+// render expands the template of the given file system with the given
+// answers, in memory
+func (u *Upgrader) render(m *Manifest, fsys fs.FS,
+	options util.StringAnyMap) (*Generator, ResultData, error) {
+	name, _ := m.Data["name"].(string)
+	if len(name) == 0 {
+		name = path.Base(u.projectDir)
+	}
+
+	env := *u.env
+	env.FS = fsys
+
+	g := NewGenerator(name).
+		Env(&env).
+		WorkingDir(path.Dir(u.projectDir)).
+		Preset(common.NewPresetDataIn(
+			fsys, m.Preset, m.TemplateDir, maps.Clone(options)))
+
+	if g.preset.GetTypeId() != common.TargetTypeProject {
+		return nil, ResultData{}, fmt.Errorf(
+			util.Msg("not a project template, dir = '%v'"), m.TemplateDir)
+	}
+
+	g.recordSources()
+	if err := g.prepContext(); err != nil {
+		return nil, ResultData{}, err
+	}
+
+	rendered, err := g.runNames()
+	if err != nil {
+		return nil, ResultData{}, err
+	}
+
+	for i := range rendered.items {
+		rendered.items[i].action = FileActionCreated
+		if err := g.runContents(&rendered.items[i]); err != nil {
+			return nil, ResultData{}, err
+		}
+	}
+
+	return g, rendered, nil
+}
+
+// renderBase returns the files as they were generated, rendered again
+// from the template files of the manifest with its answers, by path.
+// It returns nil if these template files are not in the template store,
+// like for a project generated on another machine.
+func (u *Upgrader) renderBase(m *Manifest,
+	current *Generator, rendered ResultData) (map[string][]byte, error) {
+	bases := map[string][]byte{}
+
+	id, _, err := current.sources.source()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(m.Source) != 0 && id == m.Source {
+		// the template did not change
+		for _, item := range rendered.items {
+			bases[item.outputFileRel] = item.contents
+		}
+
+		return bases, nil
+	}
+
+	fsys, found := openTemplateSource(u.env.StoreDir, m.Source)
+	if !found {
+		logrus.Warnf(util.Msg("the template files of revision '%v' "+
+			"are not available, files changed by hand cannot be merged"),
+			m.Revision)
+		return nil, nil
+	}
+
+	_, base, err := u.render(m, fsys, m.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range base.items {
+		bases[item.outputFileRel] = item.contents
+	}
+
+	return bases, nil
+}
+
+// upgradeFile merges the change of the template for one file, and
+// schedules the write. theirs is the new contents, if the template
+// still has the file, and bases the files as they were generated.
+func (u *Upgrader) upgradeFile(tx *transaction, m *Manifest, rel string,
+	theirs []byte, inTemplate bool, bases map[string][]byte) (UpgradeFile, error) {
+	file := UpgradeFile{Path: rel, State: UpgradeUnchanged}
+	target := path.Join(u.projectDir, rel)
+
+	ours, err := os.ReadFile(target)
+	exists := err == nil
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return file, err
+	}
+
+	generated := slices.ContainsFunc(m.Files, func(file ManifestFile) bool {
+		return file.Path == rel
+	})
+
+	base, hasBase := bases[rel]
+	if !hasBase && exists && u.isUnchanged(m, rel, ours) {
+		// the file is as it was generated
+		base, hasBase = ours, true
+	}
+
+	var result []byte
+
+	switch {
+	case !inTemplate:
+		if exists && hasBase && bytes.Equal(ours, base) {
+			file.State = UpgradeRemoved
+		} else if exists {
+			file.State = UpgradeKept
+		}
+
+	case !exists:
+		if !hasBase && !generated {
+			file.State = UpgradeAdded
+			result = theirs
+		} else if !hasBase || !bytes.Equal(base, theirs) {
+			file.State = UpgradeKept
+		}
+
+	case bytes.Equal(ours, theirs):
+		// nothing to do
+
+	case hasBase && bytes.Equal(ours, base):
+		file.State = UpgradeUpdated
+		result = theirs
+
+	case hasBase && bytes.Equal(base, theirs):
+		// only changed by hand
+
+	default:
+		merged, conflicts := util.Merge3(string(base), string(ours),
+			string(theirs), util.Msg("current"), util.Msg("template"))
+
+		file.State = UpgradeMerged
+		if conflicts != 0 {
+			file.State = UpgradeConflict
+			file.Conflicts = conflicts
+		}
+
+		result = []byte(merged)
+	}
+
+	if u.dryRun {
+		switch {
+		case result != nil:
+			file.Diff = util.UnifiedDiff(string(ours), string(result),
+				path.Join("a", rel), path.Join("b", rel))
+		case file.State == UpgradeRemoved:
+			file.Diff = util.UnifiedDiff(string(ours), "",
+				path.Join("a", rel), path.Join("b", rel))
+		}
+
+		return file, nil
+	}
+
+	switch {
+	case result != nil:
+		tx.Add(target, result, "")
+	case file.State == UpgradeRemoved:
+		tx.Remove(target)
+	}
+
+	return file, nil
+}
+
+func (u *Upgrader) isUnchanged(m *Manifest, rel string, contents []byte) bool {
+	return slices.ContainsFunc(m.Files, func(file ManifestFile) bool {
+		return file.Path == rel && file.Hash == HashContents(contents)
+	})
+}
+
+// HasConflicts tells whether a file got conflict markers
+func (r *UpgradeResult) HasConflicts() bool {
+	return slices.ContainsFunc(r.Files, func(file UpgradeFile) bool {
+		return file.State == UpgradeConflict
+	})
+}
+
+func (r *UpgradeResult) Print(output io.Writer) {
+	w := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+
+	for _, file := range r.Files {
+		if file.Conflicts != 0 {
+			fmt.Fprintf(w, "%s\t%s\t(%d)\n", file.State, file.Path, file.Conflicts)
+		} else {
+			fmt.Fprintf(w, "%s\t%s\t\n", file.State, file.Path)
+		}
+	}
+
+	w.Flush()
+
+	if !r.DryRun {
+		return
+	}
+
+	for _, file := range r.Files {
+		if len(file.Diff) != 0 {
+			fmt.Fprintln(output)
+			fmt.Fprint(output, file.Diff)
+		}
+	}
+}
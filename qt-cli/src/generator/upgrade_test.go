@@ -0,0 +1,163 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"qtcli/common"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpgrader(t *testing.T) {
+	fsys := fstest.MapFS{
+		"p/templates.yml": {Data: []byte(`version: "1"
+meta:
+  type: project
+files:
+  - in: main.cpp
+  - in: kept.txt
+  - in: edited.txt
+  - in: old.txt
+`)},
+		"p/main.cpp":   {Data: []byte("one\ntwo\nthree\nfour\nfive\n")},
+		"p/kept.txt":   {Data: []byte("kept\n")},
+		"p/edited.txt": {Data: []byte("{{ .name }}\n")},
+		"p/old.txt":    {Data: []byte("old\n")},
+	}
+
+	env := &Env{
+		FS:               fsys,
+		TemplateFileName: common.TemplateFileName,
+		StoreDir:         filepath.ToSlash(t.TempDir()),
+	}
+	workingDir := filepath.ToSlash(t.TempDir())
+	projectDir := path.Join(workingDir, "app")
+
+	result := NewGenerator("app").
+		Env(env).
+		WorkingDir(workingDir).
+		Preset(common.NewPresetDataIn(fsys, "@p", "p", map[string]any{})).
+		Render()
+	require.True(t, result.Success, result.Error.Message)
+
+	write := func(name, contents string) {
+		require.NoError(t, os.WriteFile(
+			path.Join(projectDir, name), []byte(contents), 0644))
+	}
+
+	write("main.cpp", "ONE\ntwo\nthree mine\nfour\nfive\n")
+	write("edited.txt", "mine\n")
+
+	// the new revision of the template
+	fsys["p/templates.yml"].Data = []byte(`version: "2"
+meta:
+  type: project
+files:
+  - in: main.cpp
+  - in: kept.txt
+  - in: edited.txt
+  - in: new.txt
+`)
+	fsys["p/main.cpp"].Data = []byte("one\ntwo\nthree theirs\nfour\nfive theirs\n")
+	fsys["p/kept.txt"].Data = []byte("kept, updated\n")
+	fsys["p/new.txt"] = &fstest.MapFile{Data: []byte("new\n")}
+
+	dryRun, err := NewUpgrader(projectDir).Env(env).DryRun(true).Run()
+	require.NoError(t, err)
+	require.Equal(t, "1", dryRun.FromRevision)
+	require.Equal(t, "2", dryRun.ToRevision)
+
+	upgrade, err := NewUpgrader(projectDir).Env(env).Run()
+	require.NoError(t, err)
+
+	states := map[string]UpgradeState{}
+	for _, file := range upgrade.Files {
+		states[file.Path] = file.State
+	}
+
+	assert.Equal(t, map[string]UpgradeState{
+		"main.cpp":   UpgradeConflict,
+		"kept.txt":   UpgradeUpdated,
+		"edited.txt": UpgradeUnchanged,
+		"old.txt":    UpgradeRemoved,
+		"new.txt":    UpgradeAdded,
+	}, states)
+	assert.True(t, upgrade.HasConflicts())
+
+	ensureContents(t, path.Join(projectDir, "main.cpp"),
+		"ONE\ntwo\n<<<<<<< current\nthree mine\n=======\nthree theirs\n"+
+			">>>>>>> template\nfour\nfive theirs\n")
+	ensureContents(t, path.Join(projectDir, "kept.txt"), "kept, updated\n")
+	ensureContents(t, path.Join(projectDir, "edited.txt"), "mine\n")
+	ensureContents(t, path.Join(projectDir, "new.txt"), "new\n")
+	assert.NoFileExists(t, path.Join(projectDir, "old.txt"))
+
+	m, err := ReadManifest(projectDir)
+	require.NoError(t, err)
+	assert.Equal(t, "2", m.Revision)
+	assert.Len(t, m.Files, 4)
+	assert.NoDirExists(t, path.Join(projectDir, ManifestDirName, "base"))
+
+	// the new revision is the base of the next upgrade
+	stored, ok := openTemplateSource(env.StoreDir, m.Source)
+	require.True(t, ok)
+	ensureContents(t, path.Join(env.StoreDir, m.Source, "p", "main.cpp"),
+		"one\ntwo\nthree theirs\nfour\nfive theirs\n")
+	_, err = fs.Stat(stored, "p/old.txt")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestUpgrader_WithoutStore(t *testing.T) {
+	fsys := fstest.MapFS{
+		"p/templates.yml": {Data: []byte(`version: "1"
+meta:
+  type: project
+files:
+  - in: main.cpp
+  - in: kept.txt
+`)},
+		"p/main.cpp": {Data: []byte("one\ntwo\n")},
+		"p/kept.txt": {Data: []byte("kept\n")},
+	}
+
+	env := &Env{FS: fsys, TemplateFileName: common.TemplateFileName}
+	workingDir := filepath.ToSlash(t.TempDir())
+	projectDir := path.Join(workingDir, "app")
+
+	result := NewGenerator("app").
+		Env(env).
+		WorkingDir(workingDir).
+		Preset(common.NewPresetDataIn(fsys, "@p", "p", map[string]any{})).
+		Render()
+	require.True(t, result.Success, result.Error.Message)
+
+	require.NoError(t, os.WriteFile(
+		path.Join(projectDir, "main.cpp"), []byte("one\nmine\n"), 0644))
+
+	fsys["p/main.cpp"].Data = []byte("one\ntheirs\n")
+	fsys["p/kept.txt"].Data = []byte("kept, updated\n")
+
+	upgrade, err := NewUpgrader(projectDir).Env(env).Run()
+	require.NoError(t, err)
+
+	states := map[string]UpgradeState{}
+	for _, file := range upgrade.Files {
+		states[file.Path] = file.State
+	}
+
+	// unchanged files are their own base, the others cannot be merged
+	assert.Equal(t, map[string]UpgradeState{
+		"main.cpp": UpgradeConflict,
+		"kept.txt": UpgradeUpdated,
+	}, states)
+
+	ensureContents(t, path.Join(projectDir, "kept.txt"), "kept, updated\n")
+}
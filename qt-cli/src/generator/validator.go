@@ -38,9 +38,11 @@ const (
 )
 
 type ValidatorIn struct {
-	Name       string
-	WorkingDir string
-	TypeId     common.TargetType
+	Name           string
+	WorkingDir     string
+	TypeId         common.TargetType
+	ConflictPolicy ConflictPolicy
+	DryRun         bool
 }
 
 func Validate(in ValidatorIn) common.Issues {
@@ -80,12 +82,18 @@ func (in *ValidatorIn) checkNameIssue(v *common.StringValidator) *common.Issue {
 			return nil
 		}
 
-		msg := common.ValidatorSameFileExists
-		if stat.IsDir() {
-			msg = common.ValidatorTargetFolderExists
+		if !stat.IsDir() {
+			return common.NewErrorIssue(
+				FieldIdName, common.ValidatorSameFileExists+": "+dir)
+		}
+
+		if in.ConflictPolicy.AllowsExisting() || in.DryRun {
+			return common.NewWarningIssue(
+				FieldIdName, common.ValidatorTargetFolderExists+": "+dir)
 		}
 
-		return common.NewErrorIssue(FieldIdName, msg+": "+dir)
+		return common.NewErrorIssue(
+			FieldIdName, common.ValidatorTargetFolderExists+": "+dir)
 	}
 
 	return nil
@@ -0,0 +1,257 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+// Package golden renders templates with given answers and compares the
+// result with checked-in files, so that changes of a template can be
+// reviewed as changes of its output.
+//
+// A directory of cases holds one directory per case:
+//
+//	<case>/case.yml     the name of the item and the answers
+//	<case>/expected/    the files the template is expected to generate
+package golden
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"qtcli/common"
+	"qtcli/generator"
+	"qtcli/util"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	CaseFileName    = "case.yml"
+	ExpectedDirName = "expected"
+
+	// TestDataDirName is where the cases of a template are looked for,
+	// inside the template directory
+	TestDataDirName = "testdata"
+)
+
+type Case struct {
+	Id       string            `yaml:"-"`
+	Dir      string            `yaml:"-"`
+	ItemName string            `yaml:"name"`
+	Answers  util.StringAnyMap `yaml:"answers"`
+}
+
+// Mismatch is a difference between the expected and the actual output
+type Mismatch struct {
+	File   string `json:"file"`
+	Reason string `json:"reason"`
+	Diff   string `json:"diff,omitempty"`
+}
+
+func (m Mismatch) String() string {
+	if len(m.Diff) == 0 {
+		return fmt.Sprintf("%s: %s", m.File, m.Reason)
+	}
+
+	return fmt.Sprintf("%s: %s\n%s", m.File, m.Reason, m.Diff)
+}
+
+// LoadCases reads every case found directly under the given directory
+func LoadCases(dir string) ([]Case, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	all := []Case{}
+
+	for _, entry := range entries {
+		caseFile := filepath.Join(dir, entry.Name(), CaseFileName)
+		if !entry.IsDir() || !util.EntryExists(caseFile) {
+			continue
+		}
+
+		raw, err := os.ReadFile(caseFile)
+		if err != nil {
+			return nil, err
+		}
+
+		c := Case{}
+		if err := yaml.Unmarshal(raw, &c); err != nil {
+			return nil, fmt.Errorf("%s: %w", caseFile, err)
+		}
+
+		c.Id = entry.Name()
+		c.Dir = filepath.Join(dir, entry.Name())
+		if len(c.ItemName) == 0 {
+			c.ItemName = c.Id
+		}
+
+		all = append(all, c)
+	}
+
+	return all, nil
+}
+
+func (c Case) ExpectedDir() string {
+	return filepath.Join(c.Dir, ExpectedDirName)
+}
+
+// Render generates the template for the case under workingDir, without
+// running hooks or touching a CMakeLists.txt, and returns the directory
+// the files were written to
+func Render(env *generator.Env,
+	templateDir string, c Case, workingDir string) (string, error) {
+	steps := []common.PromptStep{}
+	defaults := util.StringAnyMap{}
+
+	promptFile, err := common.OpenPromptFileIn(env.FS, templateDir)
+	if err == nil {
+		steps = promptFile.GetContents().Steps
+		defaults = promptFile.ExtractDefaults()
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+
+	values, issues := common.ValidateOptions(steps, c.Answers)
+	if issues.HasError() {
+		return "", common.Error{
+			Message: common.InputHasIssues,
+			Details: issues,
+		}
+	}
+
+	preset := common.NewPresetDataIn(env.FS,
+		templateDir, templateDir, util.Merge(defaults, values))
+
+	result := generator.NewGenerator(c.ItemName).
+		Env(env).
+		WorkingDir(filepath.ToSlash(workingDir)).
+		Preset(preset).
+		NoHooks(true).
+		NoCMake(true).
+		Render()
+
+	if !result.Success {
+		return "", result.Error
+	}
+
+	return filepath.FromSlash(result.Data.GetOutputDirAbs()), nil
+}
+
+// Check renders the case into a temporary directory, and compares the
+// result with the expected files. With update, the expected files are
+// replaced by the result instead.
+func Check(env *generator.Env,
+	templateDir string, c Case, update bool) ([]Mismatch, error) {
+	workingDir, err := os.MkdirTemp("", "qtcli-golden-*")
+	if err != nil {
+		return nil, err
+	}
+
+	defer os.RemoveAll(workingDir)
+
+	actualDir, err := Render(env, templateDir, c, workingDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if update {
+		return []Mismatch{}, Update(c.ExpectedDir(), actualDir)
+	}
+
+	return Compare(c.ExpectedDir(), actualDir)
+}
+
+// Compare returns the differences between two directory trees
+func Compare(expectedDir, actualDir string) ([]Mismatch, error) {
+	expected, err := listFiles(expectedDir)
+	if err != nil {
+		return nil, err
+	}
+
+	actual, err := listFiles(actualDir)
+	if err != nil {
+		return nil, err
+	}
+
+	all := []Mismatch{}
+
+	for _, name := range expected {
+		if !slices.Contains(actual, name) {
+			all = append(all, Mismatch{File: name, Reason: "missing"})
+			continue
+		}
+
+		want, err := os.ReadFile(filepath.Join(expectedDir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		got, err := os.ReadFile(filepath.Join(actualDir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		diff := util.UnifiedDiff(string(want), string(got),
+			path.Join("expected", name), path.Join("actual", name))
+		if len(diff) != 0 {
+			all = append(all, Mismatch{
+				File:   name,
+				Reason: "contents differ",
+				Diff:   diff,
+			})
+		}
+	}
+
+	for _, name := range actual {
+		if !slices.Contains(expected, name) {
+			all = append(all, Mismatch{File: name, Reason: "unexpected"})
+		}
+	}
+
+	return all, nil
+}
+
+// Update replaces the expected tree by a copy of the actual one
+func Update(expectedDir, actualDir string) error {
+	if err := os.RemoveAll(expectedDir); err != nil {
+		return err
+	}
+
+	if err := os.CopyFS(expectedDir, os.DirFS(actualDir)); err != nil {
+		return err
+	}
+
+	return os.RemoveAll(filepath.Join(expectedDir, generator.ManifestDirName))
+}
+
+// listFiles returns the files of a tree, relative to it, with slashes.
+// A missing directory has no files. The manifest of a project is left
+// out, as it records the version of qtcli rather than the template output.
+func listFiles(dir string) ([]string, error) {
+	all := []string{}
+	if !util.DirExists(dir) {
+		return all, nil
+	}
+
+	err := fs.WalkDir(os.DirFS(dir), ".",
+		func(walkingPath string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() && walkingPath == generator.ManifestDirName {
+				return fs.SkipDir
+			}
+
+			if !d.IsDir() {
+				all = append(all, walkingPath)
+			}
+
+			return nil
+		})
+
+	return all, err
+}
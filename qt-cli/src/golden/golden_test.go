@@ -0,0 +1,69 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package golden
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"qtcli/common"
+	"qtcli/generator"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Run with QTCLI_UPDATE_GOLDEN=true to accept changes of the templates
+func TestBuiltInTemplates(t *testing.T) {
+	env := &generator.Env{
+		FS:               common.TemplatesFS,
+		FileTypesBaseDir: "types",
+		TemplateFileName: common.TemplateFileName,
+	}
+
+	templateDirs := []string{
+		"projects/cpp/console",
+		"projects/cpp/qtquick",
+		"projects/cpp/qwidget",
+		"types/qml",
+		"types/ui",
+		"cpp/class",
+	}
+
+	for _, dir := range templateDirs {
+		t.Run(dir, func(t *testing.T) {
+			TestTemplate(t, env, dir, path.Join("testdata", dir))
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	expected := t.TempDir()
+	actual := t.TempDir()
+
+	os.WriteFile(filepath.Join(expected, "same.txt"), []byte("a\n"), 0644)
+	os.WriteFile(filepath.Join(actual, "same.txt"), []byte("a\n"), 0644)
+	os.WriteFile(filepath.Join(expected, "changed.txt"), []byte("a\n"), 0644)
+	os.WriteFile(filepath.Join(actual, "changed.txt"), []byte("b\n"), 0644)
+	os.WriteFile(filepath.Join(expected, "missing.txt"), []byte("a\n"), 0644)
+	os.WriteFile(filepath.Join(actual, "unexpected.txt"), []byte("a\n"), 0644)
+
+	mismatches, err := Compare(expected, actual)
+	require.NoError(t, err)
+	require.Equal(t, []Mismatch{
+		{
+			File:   "changed.txt",
+			Reason: "contents differ",
+			Diff: "--- expected/changed.txt\n+++ actual/changed.txt\n" +
+				"@@ -1 +1 @@\n-a\n+b\n",
+		},
+		{File: "missing.txt", Reason: "missing"},
+		{File: "unexpected.txt", Reason: "unexpected"},
+	}, mismatches)
+
+	require.NoError(t, Update(expected, actual))
+	mismatches, err = Compare(expected, actual)
+	require.NoError(t, err)
+	require.Empty(t, mismatches)
+}
@@ -0,0 +1,6 @@
+#include "myclass.h"
+
+myclass::myclass(QObject *parent)
+    : QObject{parent}
+{
+}
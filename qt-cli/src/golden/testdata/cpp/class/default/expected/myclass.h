@@ -0,0 +1,13 @@
+#pragma once
+
+#include <QObject>
+
+class myclass: public QObject
+{
+    Q_OBJECT
+
+public:
+    explicit myclass(QObject *parent = nullptr);
+
+Q_SIGALS:
+};
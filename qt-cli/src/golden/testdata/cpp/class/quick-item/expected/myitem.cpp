@@ -0,0 +1,6 @@
+#include "myitem.h"
+
+myitem::myitem(QQuickItem *parent)
+    : QQuickItem{parent}
+{
+}
@@ -0,0 +1,14 @@
+#pragma once
+
+#include <QQuickItem>
+
+class myitem: public QQuickItem
+{
+    Q_OBJECT
+    QML_ELEMENT
+
+public:
+    explicit myitem(QQuickItem *parent = nullptr);
+
+Q_SIGALS:
+};
@@ -0,0 +1,9 @@
+import QtQuick
+import QtQuick.Controls
+
+ApplicationWindow {
+    width: 640
+    height: 480
+    visible: true
+    title: qsTr("Hello World")
+}
@@ -0,0 +1,10 @@
+#include "dialog.h"
+
+Dialog::Dialog(QWidget *parent)
+    : QDialog(parent)
+{
+}
+
+Dialog::~Dialog()
+{
+}
@@ -0,0 +1,12 @@
+#pragma once
+
+#include <QDialog>
+
+class Dialog : public QDialog
+{
+    Q_OBJECT
+
+public:
+    explicit Dialog(QWidget *parent = nullptr);
+    ~Dialog();
+};
@@ -0,0 +1,12 @@
+#include <QApplication>
+#include "dialog.h"
+
+int main(int argc, char *argv[])
+{
+    QApplication a(argc, argv);
+
+    Dialog w;
+    w.show();
+
+    return a.exec();
+}
@@ -0,0 +1,50 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package golden
+
+import (
+	"os"
+	"qtcli/generator"
+	"qtcli/util"
+	"testing"
+)
+
+// UpdateEnvName is the environment variable which, when set to true,
+// makes TestTemplate rewrite the expected files instead of comparing
+const UpdateEnvName = "QTCLI_UPDATE_GOLDEN"
+
+// TestTemplate runs every case found in casesDir against the template as
+// a subtest, e.g.
+//
+//	func TestConsole(t *testing.T) {
+//		golden.TestTemplate(t, env, "projects/cpp/console", "testdata/console")
+//	}
+func TestTemplate(t *testing.T,
+	env *generator.Env, templateDir, casesDir string) {
+	t.Helper()
+
+	cases, err := LoadCases(casesDir)
+	if err != nil {
+		t.Fatalf("cannot load test cases: %v", err)
+	}
+
+	if len(cases) == 0 {
+		t.Fatalf("no test cases found, dir = '%v'", casesDir)
+	}
+
+	update := util.ToBool(os.Getenv(UpdateEnvName), false)
+
+	for _, c := range cases {
+		t.Run(c.Id, func(t *testing.T) {
+			mismatches, err := Check(env, templateDir, c, update)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, m := range mismatches {
+				t.Error(m.String())
+			}
+		})
+	}
+}
@@ -60,12 +60,36 @@ func (p *InputPrompt) ValidateFunc(v InputValidateFunc) *InputPrompt {
 	return p
 }
 
+func (p *InputPrompt) GetQuestion() string {
+	return p.question
+}
+
 // prompt interface
 func (p *InputPrompt) GetId() string {
 	return p.id
 }
 
+// Resume starts from an earlier answer
+func (p *InputPrompt) Resume(r prompt.Result) {
+	if p.compType != prompt.CompTypeConfirm {
+		p.value = r.String()
+		return
+	}
+
+	if r.ValueAsBool(false) {
+		p.description = "Y/n"
+		p.defaultValue = "y"
+	} else {
+		p.description = "y/N"
+		p.defaultValue = "n"
+	}
+}
+
 func (p *InputPrompt) Run() (prompt.Result, error) {
+	if plain {
+		return p.runPlain()
+	}
+
 	ti := textinput.New()
 	ti.Prompt = " "
 	ti.TextStyle = prompt.Styles.InputActive
@@ -105,6 +129,7 @@ func (p *InputPrompt) Run() (prompt.Result, error) {
 		Id:    p.GetId(),
 		Value: text,
 		Done:  model.done,
+		Back:  model.back,
 	}
 
 	if p.compType == prompt.CompTypeConfirm {
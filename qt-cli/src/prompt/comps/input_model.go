@@ -14,6 +14,7 @@ import (
 
 type InputModel struct {
 	done          bool
+	back          bool
 	prompt        *InputPrompt
 	internalModel textinput.Model
 	outputBuilder func(string) string
@@ -27,6 +28,11 @@ func (model InputModel) Init() tea.Cmd {
 func (model InputModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if isBackKey(msg) {
+			model.back = true
+			return model, tea.Quit
+		}
+
 		newModel, cmd := model.keyMsgHandler(model, msg)
 		if newModel != nil || cmd != nil {
 			return newModel, cmd
@@ -42,6 +48,10 @@ func (model InputModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (model InputModel) View() string {
+	if model.back {
+		return ""
+	}
+
 	s := &prompt.Styles
 	question := s.Question.Render(model.prompt.question)
 
@@ -79,6 +89,11 @@ func (model InputModel) View() string {
 }
 
 // helpers
+func isBackKey(msg tea.KeyMsg) bool {
+	key := msg.String()
+	return key == "esc" || key == "shift+tab"
+}
+
 func decorateErrorMsg(raw string) string {
 	if len(raw) > 0 {
 		runes := []rune(raw)
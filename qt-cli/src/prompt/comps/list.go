@@ -57,12 +57,34 @@ func (p *ListPrompt) SetChecked(index int, checked bool) {
 	}
 }
 
+func (p *ListPrompt) GetQuestion() string {
+	return p.question
+}
+
 // prompt interface
 func (p *ListPrompt) GetId() string {
 	return p.id
 }
 
+// Resume starts from an earlier answer
+func (p *ListPrompt) Resume(r prompt.Result) {
+	switch v := r.Value.(type) {
+	case prompt.SelectionItem:
+		p.initIndex = v.Index
+
+	case prompt.Selection:
+		p.SetCheckedAll(false)
+		for _, item := range v {
+			p.SetChecked(item.Index, true)
+		}
+	}
+}
+
 func (p *ListPrompt) Run() (prompt.Result, error) {
+	if plain {
+		return p.runPlain()
+	}
+
 	const listWidth = 50
 	var count = len(p.items)
 
@@ -113,5 +135,6 @@ func (p *ListPrompt) Run() (prompt.Result, error) {
 		Id:    p.GetId(),
 		Value: value,
 		Done:  model.done,
+		Back:  model.back,
 	}, nil
 }
@@ -12,6 +12,7 @@ import (
 
 type ListModel struct {
 	done          bool
+	back          bool
 	prompt        *ListPrompt
 	internalModel list.Model
 	selection     prompt.Selection
@@ -28,6 +29,11 @@ func (m ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		if isBackKey(msg) {
+			m.back = true
+			return m, tea.Quit
+		}
+
 		switch keypress := msg.String(); keypress {
 		case " ":
 			if m.prompt.multiSelect {
@@ -80,6 +86,10 @@ func (m ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m ListModel) View() string {
+	if m.back {
+		return ""
+	}
+
 	sty := &prompt.Styles
 
 	if m.done {
@@ -0,0 +1,262 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package comps
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"qtcli/prompt"
+	"qtcli/util"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// PlainBackKey is the answer which goes back to the previous question,
+// when prompts are asked line by line
+const PlainBackKey = "<"
+
+// plain makes prompts ask questions line by line, instead of running
+// a terminal UI. Questions are written to stderr so that they do not
+// mix with the results written to stdout.
+var (
+	plain              = false
+	plainIn            = bufio.NewReader(os.Stdin)
+	plainOut io.Writer = os.Stderr
+)
+
+// UsePlain switches all the prompts between the terminal UI and
+// the line based one
+func UsePlain(on bool) {
+	plain = on
+}
+
+func IsPlain() bool {
+	return plain
+}
+
+// SetPlainIO changes where the line based prompts read and write
+func SetPlainIO(in io.Reader, out io.Writer) {
+	plainIn = bufio.NewReader(in)
+	plainOut = out
+}
+
+var errNoMoreInput = errors.New(util.Msg(
+	"cannot ask questions, the input ended; " +
+		"use a preset or give the answers on the command line"))
+
+// readPlainLine writes the question and reads the answer, trimmed.
+// It tells whether the user asked to go back instead.
+func readPlainLine(question string) (string, bool, error) {
+	fmt.Fprint(plainOut, question)
+
+	line, err := plainIn.ReadString('\n')
+	if err != nil && (err != io.EOF || len(line) == 0) {
+		if err == io.EOF {
+			fmt.Fprintln(plainOut)
+			return "", false, errNoMoreInput
+		}
+
+		return "", false, err
+	}
+
+	line = strings.TrimSpace(line)
+	return line, line == PlainBackKey, nil
+}
+
+func plainQuestion(question, description, defaultValue string) string {
+	s := string(prompt.MarkingQuestion) + question
+
+	if len(description) != 0 {
+		s += " (" + description + ")"
+	}
+
+	if len(defaultValue) != 0 {
+		s += " [" + defaultValue + "]"
+	}
+
+	return s + " "
+}
+
+func (p *InputPrompt) runPlain() (prompt.Result, error) {
+	if p.compType == prompt.CompTypeConfirm {
+		return p.runPlainConfirm()
+	}
+
+	result := prompt.Result{Id: p.GetId()}
+
+	for {
+		q := plainQuestion(p.question, p.description, p.value)
+		line, back, err := readPlainLine(q)
+		if err != nil || back {
+			result.Back = back
+			return result, err
+		}
+
+		if len(line) == 0 {
+			line = p.value
+		}
+
+		if p.validator != nil {
+			if err := p.validator(line); err != nil {
+				fmt.Fprintln(plainOut, decorateErrorMsg(err.Error()))
+				continue
+			}
+		}
+
+		result.Value = line
+		result.Done = true
+		return result, nil
+	}
+}
+
+func (p *InputPrompt) runPlainConfirm() (prompt.Result, error) {
+	result := prompt.Result{Id: p.GetId()}
+
+	for {
+		q := plainQuestion(p.question, p.description, "")
+		line, back, err := readPlainLine(q)
+		if err != nil || back {
+			result.Back = back
+			return result, err
+		}
+
+		if len(line) == 0 {
+			line = p.defaultValue
+		}
+
+		switch strings.ToLower(line) {
+		case "y", "yes":
+			result.Value = true
+		case "n", "no":
+			result.Value = false
+		default:
+			fmt.Fprintln(plainOut,
+				decorateErrorMsg(util.Msg("answer y or n")))
+			continue
+		}
+
+		result.Done = true
+		return result, nil
+	}
+}
+
+func (p *ListPrompt) runPlain() (prompt.Result, error) {
+	result := prompt.Result{Id: p.GetId()}
+
+	// numbers given to the items, separators are not counted
+	indexes := []int{}
+	for index, item := range p.items {
+		if !item.IsSeparator() {
+			indexes = append(indexes, index)
+		}
+	}
+
+	fmt.Fprintln(plainOut, string(prompt.MarkingQuestion)+p.question)
+	for number, index := range indexes {
+		item := p.items[index]
+		check := ""
+		if p.multiSelect {
+			check = string(prompt.MarkingCheckBoxEmpty)
+			if item.checked {
+				check = string(prompt.MarkingCheckBoxChecked)
+			}
+		}
+
+		text := item.text
+		if len(item.description) != 0 {
+			text += " (" + item.description + ")"
+		}
+
+		fmt.Fprintf(plainOut, "  %d) %s%s\n", number+1, check, text)
+	}
+
+	defaults := []string{}
+	for number, index := range indexes {
+		if p.multiSelect && p.items[index].checked ||
+			!p.multiSelect && index == p.initIndex {
+			defaults = append(defaults, strconv.Itoa(number+1))
+		}
+	}
+
+	question := util.Msg("Enter a number")
+	if p.multiSelect {
+		question = util.Msg("Enter numbers separated by commas, or none")
+	}
+
+	for {
+		q := plainQuestion(question, "", strings.Join(defaults, ","))
+		line, back, err := readPlainLine(q)
+		if err != nil || back {
+			result.Back = back
+			return result, err
+		}
+
+		if len(line) == 0 {
+			line = strings.Join(defaults, ",")
+		}
+
+		picked, err := parsePlainNumbers(line, len(indexes))
+		if err == nil && !p.multiSelect && len(picked) != 1 {
+			err = errors.New(util.Msg("pick exactly one item"))
+		}
+
+		if err != nil {
+			fmt.Fprintln(plainOut, decorateErrorMsg(err.Error()))
+			continue
+		}
+
+		selection := prompt.Selection{}
+		for _, number := range picked {
+			index := indexes[number-1]
+			selection = append(selection, prompt.SelectionItem{
+				Index: index,
+				Text:  p.items[index].text,
+				Data:  p.items[index].data,
+			})
+		}
+
+		if p.multiSelect {
+			result.Value = selection
+		} else {
+			result.Value = selection[0]
+		}
+
+		result.Done = true
+		return result, nil
+	}
+}
+
+// parsePlainNumbers parses a list of item numbers, like '1, 3',
+// sorted and without duplicates
+func parsePlainNumbers(line string, count int) ([]int, error) {
+	all := []int{}
+	if strings.EqualFold(line, "none") {
+		return all, nil
+	}
+
+	for _, field := range strings.Split(line, ",") {
+		field = strings.TrimSpace(field)
+		if len(field) == 0 {
+			continue
+		}
+
+		number, err := strconv.Atoi(field)
+		if err != nil || number < 1 || number > count {
+			return nil, fmt.Errorf(util.Msg(
+				"invalid number, expected 1 to %d, given = '%v'"),
+				count, field)
+		}
+
+		if !slices.Contains(all, number) {
+			all = append(all, number)
+		}
+	}
+
+	slices.Sort(all)
+	return all, nil
+}
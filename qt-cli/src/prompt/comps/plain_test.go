@@ -0,0 +1,168 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package comps
+
+import (
+	"errors"
+	"fmt"
+	"qtcli/prompt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runPlain(t *testing.T, p prompt.Prompt, input string) (prompt.Result, string) {
+	out := strings.Builder{}
+	SetPlainIO(strings.NewReader(input), &out)
+	UsePlain(true)
+
+	t.Cleanup(func() {
+		UsePlain(false)
+	})
+
+	r, err := p.Run()
+	require.NoError(t, err)
+	return r, out.String()
+}
+
+func TestPlain_Input(t *testing.T) {
+	validator := func(s string) error {
+		if len(s) == 0 {
+			return errors.New("required")
+		}
+
+		return nil
+	}
+
+	all := []struct {
+		input    string
+		value    string
+		expected string
+	}{
+		{"name\n", "", "name"},
+		{"  name  \n", "", "name"},
+		{"\n", "given", "given"},
+		{"\nname\n", "", "name"},
+		{"name", "", "name"},
+	}
+
+	for _, tc := range all {
+		t.Run(fmt.Sprintf("|%q|", tc.input), func(t *testing.T) {
+			p := NewInput().Id("id").Question("Name?").
+				Value(tc.value).ValidateFunc(validator)
+
+			r, _ := runPlain(t, p, tc.input)
+			assert.True(t, r.Done)
+			assert.Equal(t, "id", r.Id)
+			assert.Equal(t, tc.expected, r.Value)
+		})
+	}
+}
+
+func TestPlain_InputErrors(t *testing.T) {
+	p := NewInput().ValidateFunc(func(s string) error {
+		return errors.New("not valid")
+	})
+
+	r, _ := runPlain(t, p, "<\n")
+	assert.True(t, r.Back)
+	assert.False(t, r.Done)
+
+	out := strings.Builder{}
+	SetPlainIO(strings.NewReader("x\n"), &out)
+	_, err := p.Run()
+	assert.ErrorIs(t, err, errNoMoreInput)
+	assert.Contains(t, out.String(), "! Not valid\n")
+}
+
+func TestPlain_Confirm(t *testing.T) {
+	all := []struct {
+		input        string
+		defaultValue string
+		expected     bool
+	}{
+		{"y\n", "n", true},
+		{"No\n", "y", false},
+		{"\n", "y", true},
+		{"\n", "n", false},
+		{"maybe\nyes\n", "n", true},
+	}
+
+	for _, tc := range all {
+		t.Run(fmt.Sprintf("|%q|", tc.input), func(t *testing.T) {
+			p := NewConfirm().DefaultValue(tc.defaultValue)
+
+			r, _ := runPlain(t, p, tc.input)
+			assert.True(t, r.Done)
+			assert.Equal(t, tc.expected, r.Value)
+		})
+	}
+}
+
+func TestPlain_Picker(t *testing.T) {
+	items := []ListItem{
+		NewItem("a").Data("dataA"),
+		NewItem("-"),
+		NewItem("b").Description("second"),
+	}
+
+	all := []struct {
+		input    string
+		expected prompt.SelectionItem
+	}{
+		{"1\n", prompt.SelectionItem{Index: 0, Text: "a", Data: "dataA"}},
+		{"2\n", prompt.SelectionItem{Index: 2, Text: "b"}},
+		{"\n", prompt.SelectionItem{Index: 2, Text: "b"}},
+		{"3\n1,2\n0\n1\n", prompt.SelectionItem{Index: 0, Text: "a", Data: "dataA"}},
+	}
+
+	for _, tc := range all {
+		t.Run(fmt.Sprintf("|%q|", tc.input), func(t *testing.T) {
+			p := NewPicker().Question("Pick").Items(items).InitIndex(2)
+
+			r, out := runPlain(t, p, tc.input)
+			assert.True(t, r.Done)
+			assert.Equal(t, tc.expected, r.Value)
+			assert.Contains(t, out, "  2) b (second)\n")
+		})
+	}
+}
+
+func TestPlain_Choices(t *testing.T) {
+	items := []ListItem{
+		NewItem("a").Checked(true),
+		NewItem("b"),
+		NewItem("c").Checked(true),
+	}
+
+	all := []struct {
+		input    string
+		expected []int
+	}{
+		{"\n", []int{0, 2}},
+		{"2\n", []int{1}},
+		{"3, 1,3\n", []int{0, 2}},
+		{"none\n", []int{}},
+		{"4\n2\n", []int{1}},
+	}
+
+	for _, tc := range all {
+		t.Run(fmt.Sprintf("|%q|", tc.input), func(t *testing.T) {
+			p := NewChoices().Items(items)
+
+			r, out := runPlain(t, p, tc.input)
+			require.True(t, r.Done)
+			assert.Contains(t, out, "1) [x]  a")
+
+			indexes := []int{}
+			for _, item := range r.Value.(prompt.Selection) {
+				indexes = append(indexes, item.Index)
+			}
+
+			assert.Equal(t, tc.expected, indexes)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package comps
+
+import (
+	"qtcli/prompt"
+	"qtcli/util"
+)
+
+// NewReview creates a picker listing the answers given in a flow, to
+// either accept them all or pick one to change
+func NewReview(entries []prompt.ReviewEntry) *ListPrompt {
+	items := []ListItem{}
+
+	for _, entry := range entries {
+		question := entry.Prompt.GetId()
+		if q, ok := entry.Prompt.(interface{ GetQuestion() string }); ok {
+			question = q.GetQuestion()
+		}
+
+		items = append(items, NewItem(question).
+			Description(entry.Result.String()).
+			Data(entry.Index))
+	}
+
+	items = append(items, NewItem("-"), NewItem(util.Msg("[Continue]")))
+
+	return NewPicker().
+		Question(util.Msg("Review the answers")).
+		Help(util.Msg("Pick an answer to change it, or continue. " +
+			"Esc goes back to the last question.")).
+		Items(items).
+		InitIndex(len(items) - 1)
+}
@@ -3,26 +3,51 @@
 
 package prompt
 
-import "errors"
+import (
+	"errors"
+	"slices"
+)
 
 type PromptFlow struct {
-	prompts []Prompt
+	steps   []flowStep
 	results map[string]Result
 
 	currentIndex int
 	aborted      bool
 
+	// the steps answered so far, in order, to know where to go back to
+	history []ReviewEntry
+
 	onPromptDone  func(Prompt, Result)
 	onPromptError func(Prompt, error)
+	createReview  func([]ReviewEntry) Prompt
+}
+
+// PromptFunc creates the prompt of a step right before it is shown, so
+// that it can depend on the answers given so far. Returning a nil prompt
+// skips the step.
+type PromptFunc func() (Prompt, error)
+
+// ReviewEntry is a step answered in a flow, as listed on the review
+type ReviewEntry struct {
+	Index  int
+	Prompt Prompt
+	Result Result
+}
+
+type flowStep struct {
+	id     string
+	create PromptFunc
 }
 
 func NewFlow() *PromptFlow {
 	flow := PromptFlow{
-		prompts: []Prompt{},
+		steps:   []flowStep{},
 		results: map[string]Result{},
 
 		currentIndex: 0,
 		aborted:      false,
+		history:      []ReviewEntry{},
 	}
 
 	flow.onPromptDone = func(p Prompt, r Result) {
@@ -37,11 +62,20 @@ func (flow *PromptFlow) IsAborted() bool {
 }
 
 func (flow *PromptFlow) Add(prompt Prompt) {
-	flow.prompts = append(flow.prompts, prompt)
+	flow.AddFunc(prompt.GetId(), func() (Prompt, error) {
+		return prompt, nil
+	})
 }
 
 func (flow *PromptFlow) AddPrompts(prompts []Prompt) {
-	flow.prompts = append(flow.prompts, prompts...)
+	for _, prompt := range prompts {
+		flow.Add(prompt)
+	}
+}
+
+// AddFunc adds a step whose prompt is created when the step is reached
+func (flow *PromptFlow) AddFunc(id string, create PromptFunc) {
+	flow.steps = append(flow.steps, flowStep{id: id, create: create})
 }
 
 func (flow *PromptFlow) GetResult(id string) Result {
@@ -64,6 +98,13 @@ func (flow *PromptFlow) SetDoneHandler(fn func(Prompt, Result)) {
 	flow.onPromptDone = fn
 }
 
+// SetReview makes the flow end with a review of all the answers, shown
+// by the prompt which fn creates. Picking an item whose data is the index
+// of an entry goes back to that step, picking any other item finishes.
+func (flow *PromptFlow) SetReview(fn func([]ReviewEntry) Prompt) {
+	flow.createReview = fn
+}
+
 func (flow *PromptFlow) RunDefaultDoneHandler(p Prompt, r Result) {
 	flow.SaveResult(r)
 	flow.currentIndex++
@@ -74,12 +115,43 @@ func (flow *PromptFlow) Run() error {
 		return errors.New("handler for done event is not registered")
 	}
 
-	for {
-		if flow.currentIndex >= len(flow.prompts) || flow.aborted {
-			break
+	for !flow.aborted {
+		if flow.currentIndex >= len(flow.steps) {
+			if flow.createReview == nil {
+				break
+			}
+
+			back, err := flow.runReview()
+			if err != nil {
+				return err
+			}
+
+			if !back {
+				break
+			}
+
+			continue
+		}
+
+		step := flow.steps[flow.currentIndex]
+		prompt, err := step.create()
+		if err != nil {
+			return err
+		}
+
+		if prompt == nil {
+			// an answer given before going back no longer applies
+			delete(flow.results, step.id)
+			flow.currentIndex++
+			continue
+		}
+
+		if earlier, ok := flow.results[prompt.GetId()]; ok {
+			if r, ok := prompt.(Resumable); ok {
+				r.Resume(earlier)
+			}
 		}
 
-		prompt := flow.prompts[flow.currentIndex]
 		result, err := prompt.Run()
 		if err != nil {
 			if flow.onPromptError != nil {
@@ -89,17 +161,80 @@ func (flow *PromptFlow) Run() error {
 			return err
 		}
 
-		if result.Done {
-			last := flow.currentIndex == (len(flow.prompts) - 1)
+		switch {
+		case result.Back:
+			flow.goBack()
+
+		case result.Done:
+			flow.history = append(flow.history, ReviewEntry{
+				Index:  flow.currentIndex,
+				Prompt: prompt,
+			})
+
 			flow.onPromptDone(prompt, result)
 
-			if last || flow.aborted {
-				break
-			}
-		} else {
+		default:
 			flow.aborted = true
 		}
 	}
 
 	return nil
 }
+
+// goBack moves to the step answered before the current one,
+// if there is one
+func (flow *PromptFlow) goBack() {
+	if len(flow.history) == 0 {
+		return
+	}
+
+	last := len(flow.history) - 1
+	flow.currentIndex = flow.history[last].Index
+	flow.history = flow.history[:last]
+}
+
+// runReview shows the answers, and tells whether the user went back to
+// one of the steps
+func (flow *PromptFlow) runReview() (bool, error) {
+	if len(flow.history) == 0 {
+		return false, nil
+	}
+
+	entries := []ReviewEntry{}
+	for _, entry := range flow.history {
+		entry.Result = flow.results[entry.Prompt.GetId()]
+		entries = append(entries, entry)
+	}
+
+	result, err := flow.createReview(entries).Run()
+	if err != nil {
+		return false, err
+	}
+
+	if result.Back {
+		flow.goBack()
+		return true, nil
+	}
+
+	if !result.Done {
+		flow.aborted = true
+		return false, nil
+	}
+
+	picked, _ := result.ValueAsSelectionItem()
+	index, ok := picked.Data.(int)
+	if !ok {
+		return false, nil
+	}
+
+	pos := slices.IndexFunc(flow.history, func(e ReviewEntry) bool {
+		return e.Index == index
+	})
+	if pos < 0 {
+		return false, nil
+	}
+
+	flow.currentIndex = index
+	flow.history = flow.history[:pos]
+	return true, nil
+}
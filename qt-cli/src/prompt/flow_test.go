@@ -0,0 +1,146 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package prompt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedPrompt answers with the next result of a shared script,
+// and records what it was resumed with
+type scriptedPrompt struct {
+	id      string
+	script  *[]Result
+	resumed *Result
+}
+
+func (p *scriptedPrompt) GetId() string {
+	return p.id
+}
+
+func (p *scriptedPrompt) Run() (Result, error) {
+	next := (*p.script)[0]
+	*p.script = (*p.script)[1:]
+	next.Id = p.id
+	return next, nil
+}
+
+func (p *scriptedPrompt) Resume(r Result) {
+	p.resumed = &r
+}
+
+func done(v any) Result {
+	return Result{Value: v, Done: true}
+}
+
+func back() Result {
+	return Result{Back: true}
+}
+
+func pick(data any) Result {
+	return Result{Value: SelectionItem{Data: data}, Done: true}
+}
+
+func TestPromptFlow_Back(t *testing.T) {
+	script := []Result{done("a1"), back(), done("a2"), done("b"), done("c")}
+	prompts := map[string]*scriptedPrompt{}
+	order := []string{}
+
+	flow := NewFlow()
+	for _, id := range []string{"a", "b", "c"} {
+		prompts[id] = &scriptedPrompt{id: id, script: &script}
+		flow.AddFunc(id, func() (Prompt, error) {
+			order = append(order, id)
+			return prompts[id], nil
+		})
+	}
+
+	require.NoError(t, flow.Run())
+	assert.False(t, flow.IsAborted())
+	assert.Empty(t, script)
+	assert.Equal(t, []string{"a", "b", "a", "b", "c"}, order)
+	assert.Equal(t, "a2", flow.GetResult("a").Value)
+	assert.Equal(t, "a1", prompts["a"].resumed.Value)
+}
+
+func TestPromptFlow_BackOnFirstStep(t *testing.T) {
+	script := []Result{back(), done("a")}
+
+	flow := NewFlow()
+	flow.Add(&scriptedPrompt{id: "a", script: &script})
+
+	require.NoError(t, flow.Run())
+	assert.Empty(t, script)
+	assert.Equal(t, "a", flow.GetResult("a").Value)
+}
+
+func TestPromptFlow_BackSkipsHiddenSteps(t *testing.T) {
+	// 'b' is only asked when 'a' is yes: going back from 'c' to 'a' and
+	// answering no drops the answer of 'b'
+	script := []Result{done(true), done("b"), back(), back(),
+		done(false), done("c")}
+	flow := NewFlow()
+
+	flow.Add(&scriptedPrompt{id: "a", script: &script})
+	flow.AddFunc("b", func() (Prompt, error) {
+		if !flow.GetResult("a").ValueAsBool(false) {
+			return nil, nil
+		}
+
+		return &scriptedPrompt{id: "b", script: &script}, nil
+	})
+	flow.Add(&scriptedPrompt{id: "c", script: &script})
+
+	require.NoError(t, flow.Run())
+	assert.Empty(t, script)
+	assert.Equal(t, false, flow.GetResult("a").Value)
+	assert.Equal(t, Result{}, flow.GetResult("b"))
+	assert.Equal(t, "c", flow.GetResult("c").Value)
+}
+
+func TestPromptFlow_Review(t *testing.T) {
+	script := []Result{done("a"), done("b"), done("c"), done("b2"), done("c")}
+	reviews := []Result{pick(1), pick(nil)}
+	listed := [][]string{}
+
+	flow := NewFlow()
+	for _, id := range []string{"a", "b", "c"} {
+		flow.Add(&scriptedPrompt{id: id, script: &script})
+	}
+
+	flow.SetReview(func(entries []ReviewEntry) Prompt {
+		answers := []string{}
+		for _, e := range entries {
+			answers = append(answers, e.Result.String())
+		}
+
+		listed = append(listed, answers)
+		return &scriptedPrompt{id: "review", script: &reviews}
+	})
+
+	require.NoError(t, flow.Run())
+	assert.False(t, flow.IsAborted())
+	assert.Empty(t, script)
+	assert.Empty(t, reviews)
+	assert.Equal(t, [][]string{{"a", "b", "c"}, {"a", "b2", "c"}}, listed)
+	assert.Equal(t, "b2", flow.GetResult("b").Value)
+}
+
+func TestPromptFlow_Abort(t *testing.T) {
+	script := []Result{done("a"), {}}
+
+	flow := NewFlow()
+	flow.Add(&scriptedPrompt{id: "a", script: &script})
+	flow.Add(&scriptedPrompt{id: "b", script: &script})
+	flow.SetReview(func(entries []ReviewEntry) Prompt {
+		t.Fatal("an aborted flow is not reviewed")
+		return nil
+	})
+
+	require.NoError(t, flow.Run())
+	assert.True(t, flow.IsAborted())
+}
@@ -8,6 +8,12 @@ type Prompt interface {
 	Run() (Result, error)
 }
 
+// Resumable is implemented by prompts which can start from an earlier
+// answer, when the user goes back to them
+type Resumable interface {
+	Resume(Result)
+}
+
 type CompType string
 
 const (
@@ -4,13 +4,18 @@
 package prompt
 
 import (
+	"fmt"
 	"qtcli/util"
+	"strings"
 )
 
 type Result struct {
 	Id    string
 	Value ResultValue
 	Done  bool
+
+	// the user asked to go back to the previous prompt
+	Back bool
 }
 
 func (r Result) ValueNormalized() any {
@@ -26,6 +31,35 @@ func (r Result) ValueNormalized() any {
 	}
 }
 
+// String returns the value as shown to the user
+func (r Result) String() string {
+	switch v := r.Value.(type) {
+	case SelectionItem:
+		return v.Text
+
+	case Selection:
+		all := []string{}
+		for _, item := range v {
+			all = append(all, item.Text)
+		}
+
+		return strings.Join(all, ", ")
+
+	case bool:
+		if v {
+			return "Yes"
+		}
+
+		return "No"
+
+	case nil:
+		return ""
+
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
 func (r Result) ValueAsBool(defaultValue bool) bool {
 	return util.ToBool(r.Value, defaultValue)
 }
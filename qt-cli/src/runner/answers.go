@@ -0,0 +1,148 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"qtcli/common"
+	"qtcli/util"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// Answers holds prompt answers given up front,
+// e.g. from the command line, instead of asking the user.
+type Answers struct {
+	Values         util.StringAnyMap
+	AcceptDefaults bool
+}
+
+func (a Answers) IsGiven() bool {
+	return a.AcceptDefaults || len(a.Values) != 0
+}
+
+// ParseAnswerArgs converts 'key=value' pairs to answers
+func ParseAnswerArgs(args []string) (util.StringAnyMap, error) {
+	all := util.StringAnyMap{}
+
+	for _, arg := range args {
+		key, value, found := strings.Cut(arg, "=")
+		key = strings.TrimSpace(key)
+
+		if !found || len(key) == 0 {
+			return util.StringAnyMap{}, fmt.Errorf(
+				util.Msg("invalid answer, expected 'key=value', given = '%v'"),
+				arg)
+		}
+
+		all[key] = value
+	}
+
+	return all, nil
+}
+
+// ReadAnswersFile reads answers from a YAML or JSON file
+func ReadAnswersFile(filePath string) (util.StringAnyMap, error) {
+	raw, err := os.ReadFile(filePath)
+	if err != nil {
+		return util.StringAnyMap{}, err
+	}
+
+	all := util.StringAnyMap{}
+	if err := yaml.Unmarshal(raw, &all); err != nil {
+		return util.StringAnyMap{}, fmt.Errorf(
+			util.Msg("cannot parse answers, file = '%v': %w"), filePath, err)
+	}
+
+	return all, nil
+}
+
+// ApplyAnswers returns a copy of the given preset whose options are
+// overridden by the answers, after checking them against the prompt steps
+// of the preset's template. When accepting defaults, the options neither
+// answered nor saved in the preset take the defaults of their steps.
+// The options of the steps which would not be asked, because of their
+// 'when' condition, get their defaults back.
+func ApplyAnswers(p common.Preset, a Answers) (common.Preset, error) {
+	steps := []common.PromptStep{}
+	defaults := util.StringAnyMap{}
+	promptFile, err := common.OpenPromptFileIn(
+		GeneratorEnv.FS, p.GetTemplateDir())
+
+	if err == nil {
+		steps = promptFile.GetContents().Steps
+		defaults = promptFile.ExtractDefaults()
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	values, issues := common.ValidateOptions(steps, a.Values)
+	if issues.HasError() {
+		return nil, common.Error{
+			Message: common.InputHasIssues,
+			Details: issues,
+		}
+	}
+
+	options := util.Merge(p.GetOptions(), values)
+	if a.AcceptDefaults {
+		options = util.Merge(defaults, options)
+	}
+
+	options, issues = common.ResolveWhenConditions(
+		steps, defaults, options, values)
+	if issues.HasError() {
+		return nil, common.Error{
+			Message: common.InputHasIssues,
+			Details: issues,
+		}
+	}
+
+	return common.NewPresetData(
+		p.GetName(),
+		p.GetTemplateDir(),
+		options,
+	), nil
+}
+
+// FindPresetWithAnswers finds or selects a preset like
+// FindPresetOrRunSelector, then applies the given answers to it. When
+// accepting defaults without a preset name, the first default preset of
+// the type is used instead of asking.
+func FindPresetWithAnswers(
+	t common.TargetType, givenPresetName string, a Answers) (common.Preset, error) {
+	var preset common.Preset
+	var err error
+
+	if len(givenPresetName) == 0 && a.AcceptDefaults {
+		preset, err = findFirstDefaultPreset(t)
+	} else {
+		preset, err = FindPresetOrRunSelector(t, givenPresetName)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !a.IsGiven() {
+		return preset, nil
+	}
+
+	return ApplyAnswers(preset, a)
+}
+
+func findFirstDefaultPreset(t common.TargetType) (common.Preset, error) {
+	all := Presets.Default.FindByType(t)
+	if len(all) == 0 {
+		return nil, errors.New(common.ServerNoPresets)
+	}
+
+	logrus.Infof(util.Msg("using the default preset %v"), all[0].GetName())
+	return all[0], nil
+}
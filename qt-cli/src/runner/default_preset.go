@@ -83,6 +83,7 @@ func loadPresets(baseFS fs.FS, t common.TargetType) []common.PresetData {
 	if err == nil {
 		for _, dir := range dirs {
 			p := common.NewPresetData("@"+dir, dir, readDefaultOptions(baseFS, dir))
+			p.SetScope(common.PresetScopeDefault)
 			all = append(all, p)
 		}
 	}
@@ -105,7 +106,8 @@ func findAllTemplateDirNames(
 			if d.IsDir() && walkingPath != "." {
 				fullPath := path.Join(walkingPath, common.TemplateFileName)
 				templateFile, err := common.OpenTemplateFile(baseFS, fullPath)
-				if err == nil && templateFile.GetTargetType() == t {
+				if err == nil && !templateFile.IsAbstract() &&
+					templateFile.GetTargetType() == t {
 					found = append(found, walkingPath)
 				}
 			}
@@ -117,10 +119,8 @@ func findAllTemplateDirNames(
 }
 
 func readDefaultOptions(baseFS fs.FS, templateDir string) util.StringAnyMap {
-	fullPath := path.Join(templateDir, common.PromptFileName)
-
-	f := common.NewPromptFileFS(baseFS, fullPath)
-	if err := f.Open(); err != nil {
+	f, err := common.OpenPromptFileIn(baseFS, templateDir)
+	if err != nil {
 		return util.StringAnyMap{}
 	}
 
@@ -6,6 +6,7 @@ package runner
 import (
 	"os"
 	"path"
+	"path/filepath"
 	"qtcli/common"
 	"qtcli/generator"
 
@@ -17,6 +18,7 @@ var GeneratorEnv *generator.Env
 var Presets struct {
 	Default DefaultPresetManager
 	User    common.UserPresetManager
+	Project common.UserPresetManager
 	Any     common.CompositePresetManager
 }
 
@@ -25,34 +27,96 @@ func init() {
 		FS:               common.TemplatesFS,
 		FileTypesBaseDir: "types",
 		TemplateFileName: common.TemplateFileName,
+		StoreDir:         templateStoreDir(),
 	}
+}
 
-	// user presets
-	home, err := os.UserHomeDir()
+// templateStoreDir returns where the template files of generated projects
+// are kept for upgrades, in the cache directory of the user
+func templateStoreDir() string {
+	dir, err := os.UserCacheDir()
 	if err != nil {
-		logrus.Fatal(err)
+		return ""
+	}
+
+	return filepath.ToSlash(filepath.Join(dir, "qtcli", "templates"))
+}
+
+// UseTemplateDirs puts the given directories on top of the template roots
+// found by default. Presets are loaded from the new roots by LoadPresets.
+func UseTemplateDirs(dirs []string) {
+	if len(dirs) == 0 {
+		return
 	}
 
-	fullPath := path.Join(home, common.UserPresetFileName)
-	userPresets := common.NewUserPresetFile(fullPath)
-	if err := userPresets.Open(); err != nil {
-		logrus.Fatal(err)
+	common.UseTemplateDirs(dirs)
+	GeneratorEnv.FS = common.TemplatesFS
+}
+
+// LoadPresets reads the default presets of the template roots, and the
+// presets of the user and of the project. Files which cannot be read are
+// reported, and give no presets.
+func LoadPresets() {
+	// user presets, without a home directory the file has no path
+	userPresets := common.NewUserPresetFile("")
+	if home, err := os.UserHomeDir(); err != nil {
+		logrus.Warn(err)
+	} else {
+		fullPath := path.Join(home, common.UserPresetFileName)
+		userPresets = common.NewUserPresetFile(fullPath)
+		if err := userPresets.Open(); err != nil {
+			logrus.Warn(err)
+		}
+	}
+
+	// project presets, shared through version control;
+	// without them, the file stays empty and has no path
+	projectPresets := common.NewUserPresetFile("").
+		Scope(common.PresetScopeProject)
+
+	if cwd, err := os.Getwd(); err == nil {
+		home, _ := os.UserHomeDir()
+		found, ok := common.FindProjectPresetFile(
+			filepath.ToSlash(cwd), filepath.ToSlash(home))
+
+		if ok {
+			projectPresets = common.NewUserPresetFile(found).
+				Scope(common.PresetScopeProject)
+
+			if err := projectPresets.Open(); err != nil {
+				logrus.Warn(err)
+			}
+		}
 	}
 
 	// preset managers
+	projectPresetManager := common.NewUserPresetManager(projectPresets)
 	userPresetManager := common.NewUserPresetManager(userPresets)
 	defaultPresetManager := NewDefaultPresetManager(GeneratorEnv.FS)
 
 	Presets = struct {
 		Default DefaultPresetManager
 		User    common.UserPresetManager
+		Project common.UserPresetManager
 		Any     common.CompositePresetManager
 	}{
 		Default: defaultPresetManager,
 		User:    userPresetManager,
+		Project: projectPresetManager,
 		Any: common.NewCompositePresetManager(
+			projectPresetManager,
 			userPresetManager,
 			defaultPresetManager,
 		),
 	}
 }
+
+// RefreshPresets reads the project and user presets again if their files
+// were changed by another process since they were read
+func RefreshPresets() {
+	for _, m := range []common.UserPresetManager{Presets.Project, Presets.User} {
+		if err := m.GetFile().Refresh(); err != nil {
+			logrus.Warn(err)
+		}
+	}
+}
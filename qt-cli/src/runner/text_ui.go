@@ -6,34 +6,38 @@ package runner
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"path"
 	"qtcli/common"
+	"qtcli/generator"
 	"qtcli/prompt"
 	"qtcli/prompt/comps"
 	"qtcli/util"
-	"regexp"
 	"strings"
+
+	"github.com/sirupsen/logrus"
 )
 
-func RunPromptFromDir(dir string) (util.StringAnyMap, error) {
-	fullPath := path.Join(dir, common.PromptFileName)
+// UsePlainPrompts makes questions be asked line by line instead of in
+// a terminal UI. This is always the case when stdin or stdout is not
+// a terminal.
+func UsePlainPrompts(forced bool) {
+	comps.UsePlain(forced || !util.IsInteractive())
+}
 
+func RunPromptFromDir(dir string) (util.StringAnyMap, error) {
 	// note,
 	// the absence of prompt definition isn't considered as an error
 	// it means there is nothing to ask to the user.
-	if !util.EntryExistsFS(GeneratorEnv.FS, fullPath) {
-		return util.StringAnyMap{}, nil
-	}
-
-	promptFile := common.NewPromptFileFS(GeneratorEnv.FS, fullPath)
-	if err := promptFile.Open(); err != nil {
+	promptFile, err := common.OpenPromptFileIn(GeneratorEnv.FS, dir)
+	if err != nil {
 		return util.StringAnyMap{}, nil
 	}
 
 	return RunPrompt(promptFile)
 }
 
-func RunFilePromptByExt(ext string) (common.Preset, error) {
+func RunFilePromptByExt(ext string, a Answers) (common.Preset, error) {
 	extName := ext[1:]
 	templateDir := path.Join(GeneratorEnv.FileTypesBaseDir, extName)
 
@@ -42,6 +46,12 @@ func RunFilePromptByExt(ext string) (common.Preset, error) {
 			util.Msg("not supported file format, given = %v"), ext)
 	}
 
+	if a.IsGiven() {
+		defaults := readDefaultOptions(GeneratorEnv.FS, templateDir)
+		return ApplyAnswers(
+			common.NewPresetData(extName, templateDir, defaults), a)
+	}
+
 	options, err := RunPromptFromDir(templateDir)
 	if err != nil {
 		return nil, err
@@ -125,23 +135,28 @@ func runManualConfig(t common.TargetType) (common.Preset, error) {
 
 	if len(newName) != 0 {
 		presetData.Name = newName
-		Presets.User.GetFile().Add(presetData)
-		Presets.User.GetFile().Save()
+		err := Presets.User.GetFile().Update(func(f *common.UserPresetFile) error {
+			return f.Add(presetData)
+		})
+
+		if err != nil {
+			logrus.Warn(err)
+		}
 	}
 
 	return presetData, nil
 }
 
-func RunFileNamePrompt() string {
+func RunFileNamePrompt() (string, error) {
 	r, err := comps.NewInput().
 		Question(util.Msg("Enter the file name:")).
 		Run()
 
 	if r.Done && err == nil {
-		return strings.TrimSpace(r.Value.(string))
+		return strings.TrimSpace(r.Value.(string)), nil
 	}
 
-	return ""
+	return "", err
 }
 
 func runPresetSavePrompt() string {
@@ -149,7 +164,8 @@ func runPresetSavePrompt() string {
 		comps.NewConfirm().
 			Id("confirm").
 			Question(util.Msg("Save for later use?")).
-			Description("Y/n"),
+			Description("Y/n").
+			DefaultValue("y"),
 
 		comps.NewInput().
 			Id("name").Question(util.Msg("Enter the preset name:")),
@@ -198,36 +214,53 @@ func createPickerItems(presets []common.PresetData) []comps.ListItem {
 	return items
 }
 
+// RunPrompt asks the questions of the prompt file, in a flow where the
+// user can go back to the previous question and review all the answers
+// before they are used
 func RunPrompt(f *common.PromptFile) (util.StringAnyMap, error) {
-	answers := f.ExtractDefaults()
+	steps := f.GetContents().Steps
+	defaults := f.ExtractDefaults()
+	answers := maps.Clone(defaults)
 	expander := util.NewTemplateExpander().Data(answers)
+	flow := prompt.NewFlow()
 
-	for _, step := range f.GetContents().Steps {
-		expander.Name(fmt.Sprintf("steps:%v", step.Id))
-		okayToRun, err := expander.RunStringToBool(step.When, true)
-		if err != nil {
-			return util.StringAnyMap{}, err
-		}
+	for _, step := range steps {
+		flow.AddFunc(step.Id, func() (prompt.Prompt, error) {
+			expander.Name(fmt.Sprintf("steps:%v", step.Id))
+			okayToRun, err := expander.RunStringToBool(step.When, true)
+			if err != nil {
+				return nil, err
+			}
 
-		if !okayToRun {
-			continue
-		}
+			if !okayToRun {
+				// it may have been answered before going back
+				answers[step.Id] = defaults[step.Id]
+				return nil, nil
+			}
 
-		prompt, err := createPrompt(step, expander)
-		if err != nil {
-			return util.StringAnyMap{}, err
-		}
+			return createPrompt(step, expander)
+		})
+	}
 
-		result, err := prompt.Run()
-		if err != nil {
-			return util.StringAnyMap{}, err
-		}
+	flow.SetDoneHandler(func(p prompt.Prompt, r prompt.Result) {
+		answers[p.GetId()] = r.ValueNormalized()
+		flow.RunDefaultDoneHandler(p, r)
+	})
 
-		if !result.Done {
-			return util.StringAnyMap{}, errors.New("aborted")
-		}
+	// line by line, going back with '<' is enough, and a review would
+	// read one more line than the questions
+	if !comps.IsPlain() {
+		flow.SetReview(func(entries []prompt.ReviewEntry) prompt.Prompt {
+			return comps.NewReview(entries)
+		})
+	}
 
-		answers[step.Id] = result.ValueNormalized()
+	if err := flow.Run(); err != nil {
+		return util.StringAnyMap{}, err
+	}
+
+	if flow.IsAborted() {
+		return util.StringAnyMap{}, errors.New(util.Msg("aborted"))
 	}
 
 	return answers, nil
@@ -297,30 +330,18 @@ func createPrompt(
 func createInputValidator(
 	fieldName string,
 	rules []common.PromptInputRules) (comps.InputValidateFunc, error) {
-	// compose validation tags
-	tags := []string{}
-
-	for _, input := range rules {
-		for name, value := range input {
-			tag, err := createInputValidatorTag(name, value)
-			if err != nil {
-				return nil, err
-			}
-
-			if len(tag) != 0 {
-				tags = append(tags, tag)
-			}
-		}
+	tag, err := common.NewTagFromRules(rules)
+	if err != nil {
+		return nil, err
 	}
 
 	// nothing to validate
-	if len(tags) == 0 {
+	if len(tag) == 0 {
 		return nil, nil
 	}
 
 	// create validation function
 	v := common.NewStringValidator()
-	tag := strings.Join(tags, ",")
 
 	return func(data string) error {
 		issue := v.Run(fieldName, data, tag)
@@ -332,41 +353,6 @@ func createInputValidator(
 	}, nil
 }
 
-func createInputValidatorTag(name string, value any) (string, error) {
-	aname := strings.ToLower(strings.TrimSpace(name))
-
-	if aname == common.TagRequired {
-		avalue, ok := value.(bool)
-		if !ok {
-			return "", errors.New(
-				util.Msg("invalid argument: boolean expected"))
-		}
-
-		if avalue {
-			return common.TagRequired, nil
-		}
-
-		return "", nil
-	}
-
-	if aname == common.TagMatch {
-		pattern, ok := value.(string)
-		if !ok {
-			return "", errors.New(
-				util.Msg("invalid argument: string expected"))
-		}
-
-		_, err := regexp.Compile(pattern)
-		if err != nil {
-			return "", fmt.Errorf(util.Msg("invalid pattern: '%w'"), pattern)
-		}
-
-		return common.TagMatch + "=" + pattern, nil
-	}
-
-	return "", nil
-}
-
 func createListItems(
 	step common.PromptStep,
 	expander *util.TemplateExpander) ([]comps.ListItem, error) {
@@ -399,3 +385,33 @@ func createListItems(
 
 	return all, nil
 }
+
+func RunConflictPrompt(outputFileAbs string) (generator.ConflictPolicy, error) {
+	items := []comps.ListItem{
+		comps.NewItem(util.Msg("Skip")).
+			Data(generator.ConflictSkip),
+		comps.NewItem(util.Msg("Overwrite")).
+			Data(generator.ConflictOverwrite),
+		comps.NewItem(util.Msg("Back up and overwrite")).
+			Data(generator.ConflictBackup),
+		comps.NewItem(util.Msg("Abort")).
+			Data(generator.ConflictFail),
+	}
+
+	r, err := comps.NewPicker().
+		Question(fmt.Sprintf(
+			util.Msg("File already exists, %s"), outputFileAbs)).
+		Items(items).
+		Run()
+	if err != nil {
+		return generator.ConflictFail, err
+	}
+
+	if !r.Done {
+		return generator.ConflictFail, errors.New(util.Msg("aborted"))
+	}
+
+	picked, _ := r.ValueAsSelectionItem()
+	policy, _ := picked.Data.(generator.ConflictPolicy)
+	return policy, nil
+}
@@ -0,0 +1,31 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"qtcli/common"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireToken is a middleware rejecting the requests which do not give
+// the session token of the server, as 'Authorization: Bearer <token>'
+func RequireToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		given, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare(
+			[]byte(strings.TrimSpace(given)), []byte(token)) != 1 {
+			c.Header("WWW-Authenticate", "Bearer")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{
+				Error: common.ServerUnauthorized,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.ReleaseMode)
+}
+
+func TestHandler_RequireToken(t *testing.T) {
+	cases := []struct {
+		header       string
+		expectedCode int
+	}{
+		{"Bearer secret", http.StatusOK},
+		{"Bearer  secret ", http.StatusOK},
+		{"", http.StatusUnauthorized},
+		{"secret", http.StatusUnauthorized},
+		{"Bearer other", http.StatusUnauthorized},
+		{"Bearer secret2", http.StatusUnauthorized},
+		{"Basic secret", http.StatusUnauthorized},
+	}
+
+	r := gin.New()
+	r.Use(RequireToken("secret"))
+	r.GET("/ready", GetReady)
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("|%s|%v|", tc.header, tc.expectedCode), func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/ready", nil)
+			if len(tc.header) != 0 {
+				req.Header.Set("Authorization", tc.header)
+			}
+
+			r.ServeHTTP(w, req)
+			ensureHttpCode(t, w, tc.expectedCode)
+
+			if tc.expectedCode == http.StatusUnauthorized {
+				ensureResponseType[ErrorResponse](t, w)
+			}
+		})
+	}
+}
@@ -4,8 +4,11 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"qtcli/common"
+	"qtcli/runner"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -42,12 +45,29 @@ func ReplyStatus(c *gin.Context, msg string) {
 }
 
 func ReplyError(c *gin.Context, msg string, details *common.Issues) {
+	c.JSON(http.StatusBadRequest, NewErrorResponse(msg, details))
+}
+
+// NewErrorResponse leaves the details out when there are none
+func NewErrorResponse(msg string, details *common.Issues) ErrorResponse {
 	e := ErrorResponse{Error: msg}
 	if details != nil && len(*details) != 0 {
 		e.Details = details
 	}
 
-	c.JSON(http.StatusBadRequest, e)
+	return e
+}
+
+// ReplyErrorOf replies the issues of a common.Error, or the message
+// of any other error
+func ReplyErrorOf(c *gin.Context, err error) {
+	var e common.Error
+	if errors.As(err, &e) {
+		ReplyError(c, e.Message, &e.Details)
+		return
+	}
+
+	ReplyErrorMsg(c, err.Error())
 }
 
 func ReplyErrorMsg(c *gin.Context, msg string) {
@@ -55,3 +75,48 @@ func ReplyErrorMsg(c *gin.Context, msg string) {
 		Error: msg,
 	})
 }
+
+// ReplyPresetChanged replies that the preset given in If-Match is
+// outdated, so that the client reads it again before changing it
+func ReplyPresetChanged(c *gin.Context) {
+	c.JSON(http.StatusPreconditionFailed, ErrorResponse{
+		Error: common.ServerPresetChanged,
+	})
+}
+
+// errPresetChanged is returned from preset file updates when the
+// preset does not match the If-Match header of the request
+var errPresetChanged = errors.New(common.ServerPresetChanged)
+
+// SetPresetETag gives the revision of the preset, to be sent back
+// in the If-Match header of a later change
+func SetPresetETag(c *gin.Context, p common.PresetData) {
+	c.Header("ETag", `"`+p.GetRevision()+`"`)
+}
+
+// matchesIfMatch tells if the preset is the one the client read, that is
+// if its revision is listed in the If-Match header. Requests without this
+// header always match. Weak tags never match, since If-Match compares
+// tags strongly (RFC 9110, section 13.1.1).
+func matchesIfMatch(c *gin.Context, p common.PresetData) bool {
+	header := c.GetHeader("If-Match")
+	if len(header) == 0 {
+		return true
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == `"`+p.GetRevision()+`"` {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RefreshPresets is a middleware reading the preset files again
+// when they were changed by another process, like the command line
+func RefreshPresets(c *gin.Context) {
+	runner.RefreshPresets()
+	c.Next()
+}
@@ -4,11 +4,9 @@
 package handlers
 
 import (
-	"os"
+	"errors"
 	"qtcli/common"
 	"qtcli/runner"
-	"qtcli/util"
-	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -19,37 +17,53 @@ type PresetDeleteResponse struct {
 	Status   string `json:"status" binding:"required"`
 }
 
+// shutdown stops the server, after the requests being served
+var shutdown = func() {}
+
+// SetShutdown tells DeleteServer how to stop the server
+func SetShutdown(fn func()) {
+	shutdown = fn
+}
+
 func DeleteServer(c *gin.Context) {
 	ReplyStatus(c, common.ServerClosing)
-
-	go func() {
-		time.Sleep(1 * time.Second)
-		util.SendSigTermOrKill(os.Getpid())
-	}()
+	go shutdown()
 }
 
 func DeleteCustomPresetById(c *gin.Context) {
-	id := c.Param("id")
 	var preset common.PresetData
-	var err error
+	id := c.Param("id")
 
-	if id != "" {
-		preset, err = runner.Presets.User.FindByUniqueId(id)
-	} else {
+	if id == "" {
 		ReplyErrorMsg(c, common.ServerNoPreset)
 		return
 	}
 
-	if err != nil {
-		ReplyErrorMsg(c, common.ServerNoPreset)
+	err := runner.Presets.User.GetFile().Update(
+		func(f *common.UserPresetFile) error {
+			found, err := f.FindByUniqueId(id)
+			if err != nil {
+				return errors.New(common.ServerNoPreset)
+			}
+
+			if !matchesIfMatch(c, found) {
+				return errPresetChanged
+			}
+
+			preset = found
+			return f.Remove(preset.Name)
+		})
+
+	if errors.Is(err, errPresetChanged) {
+		ReplyPresetChanged(c)
 		return
 	}
 
-	f := runner.Presets.User.GetFile()
-	f.Remove(preset.Name)
-	f.Save()
+	if err != nil {
+		ReplyErrorMsg(c, err.Error())
+		return
+	}
 
-	// TODO: error handling in case of fail
 	ReplyDelete(c, PresetDeleteResponse{
 		Name:     preset.Name,
 		PresetId: preset.GetUniqueId(),
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -16,10 +17,20 @@ func init() {
 }
 
 func TestHandler_DeleteServer(t *testing.T) {
+	stopped := make(chan struct{})
+	SetShutdown(func() { close(stopped) })
+	t.Cleanup(func() { SetShutdown(func() {}) })
+
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
 
 	DeleteServer(ctx)
 	ensureHttpCode(t, w, http.StatusOK)
 	ensureResponseType[StatusResponse](t, w)
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the server was not stopped")
+	}
 }
@@ -4,7 +4,6 @@
 package handlers
 
 import (
-	"path"
 	"qtcli/common"
 	"qtcli/runner"
 
@@ -12,9 +11,10 @@ import (
 )
 
 type PresetsResponseItem struct {
-	Id   string              `json:"id"`
-	Name string              `json:"name"`
-	Meta common.TemplateMeta `json:"meta"`
+	Id    string              `json:"id"`
+	Name  string              `json:"name"`
+	Scope common.PresetScope  `json:"scope"`
+	Meta  common.TemplateMeta `json:"meta"`
 }
 
 type PresetsResponse []PresetsResponseItem
@@ -22,6 +22,7 @@ type PresetsResponse []PresetsResponseItem
 type PresetDetailResponse struct {
 	Id     string                     `json:"id"`
 	Name   string                     `json:"name"`
+	Scope  common.PresetScope         `json:"scope"`
 	Meta   common.TemplateMeta        `json:"meta"`
 	Prompt *common.PromptFileContents `json:"prompt,omitempty"`
 }
@@ -52,21 +53,10 @@ func GetPresetsByNameOrType(c *gin.Context) {
 		return
 	}
 
-	res := PresetsResponse{}
-	for _, p := range presets {
-		template, err := common.OpenTemplateFileIn(
-			runner.GeneratorEnv.FS, p.GetTemplateDir())
-
-		if err != nil {
-			ReplyErrorMsg(c, common.ServerNoTemplateFile)
-			return
-		}
-
-		res = append(res, PresetsResponseItem{
-			Id:   p.GetUniqueId(),
-			Name: p.GetName(),
-			Meta: template.GetMeta(),
-		})
+	res, err := NewPresetsResponse(presets)
+	if err != nil {
+		ReplyErrorMsg(c, common.ServerNoTemplateFile)
+		return
 	}
 
 	ReplyGet(c, res)
@@ -92,34 +82,68 @@ func getPresetBy(c *gin.Context, value, by string) {
 		return
 	}
 
-	template, err := common.OpenTemplateFileIn(
-		runner.GeneratorEnv.FS, p.GetTemplateDir())
+	res, err := NewPresetDetailResponse(p)
 	if err != nil {
 		ReplyErrorMsg(c, common.ServerNoTemplateFile)
 		return
 	}
 
+	SetPresetETag(c, p)
+	ReplyGet(c, res)
+}
+
+// NewPresetsResponse describes the given presets. It fails when the
+// template file of a preset cannot be opened.
+func NewPresetsResponse(presets []common.PresetData) (PresetsResponse, error) {
+	res := PresetsResponse{}
+	for _, p := range presets {
+		template, err := common.OpenTemplateFileIn(
+			runner.GeneratorEnv.FS, p.GetTemplateDir())
+
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, PresetsResponseItem{
+			Id:    p.GetUniqueId(),
+			Name:  p.GetName(),
+			Scope: p.GetScope(),
+			Meta:  template.GetMeta(),
+		})
+	}
+
+	return res, nil
+}
+
+// NewPresetDetailResponse describes the given preset, with the options of
+// the preset as the default values of its prompt
+func NewPresetDetailResponse(p common.PresetData) (PresetDetailResponse, error) {
+	template, err := common.OpenTemplateFileIn(
+		runner.GeneratorEnv.FS, p.GetTemplateDir())
+	if err != nil {
+		return PresetDetailResponse{}, err
+	}
+
 	prompt := getPromptFileContents(p.GetTemplateDir())
 	if prompt != nil {
 		prompt.UpdateDefaultValues(p.GetOptions())
 	}
 
-	ReplyGet(c, PresetDetailResponse{
+	return PresetDetailResponse{
 		Id:     p.GetUniqueId(),
 		Name:   p.GetName(),
+		Scope:  p.GetScope(),
 		Meta:   template.GetMeta(),
 		Prompt: prompt,
-	})
+	}, nil
 }
 
 func getPromptFileContents(dir string) *common.PromptFileContents {
 	// note,
 	// the absence of prompt definition isn't considered as an error
 	// it means there is nothing to ask to the user.
-	fullPath := path.Join(dir, common.PromptFileName)
-	promptFile := common.NewPromptFileFS(runner.GeneratorEnv.FS, fullPath)
-
-	if err := promptFile.Open(); err == nil {
+	promptFile, err := common.OpenPromptFileIn(runner.GeneratorEnv.FS, dir)
+	if err == nil {
 		return promptFile.GetContents()
 	}
 
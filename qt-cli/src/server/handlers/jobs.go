@@ -0,0 +1,50 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package handlers
+
+import (
+	"qtcli/generator"
+)
+
+// Names of the events of a job, besides the ones of the generator.
+// The last event of a job is either done, with a NewItemResponse,
+// or error, with an ErrorResponse.
+const (
+	JobEventDone  = "done"
+	JobEventError = "error"
+)
+
+// JobFileEvent is the data of file-rendered and file-written events
+type JobFileEvent struct {
+	File   string `json:"file" binding:"required"`
+	Action string `json:"action" binding:"required"`
+	Backup string `json:"backup,omitempty"`
+}
+
+type JobWarningEvent struct {
+	Message string `json:"message" binding:"required"`
+}
+
+// JobHookOutputEvent carries one line of the output of a hook
+type JobHookOutputEvent struct {
+	Hook string `json:"hook" binding:"required"`
+	Line string `json:"line" binding:"required"`
+}
+
+// NewJobEventData returns the data sent with an event of the generator
+func NewJobEventData(e generator.Event) any {
+	switch e.Type {
+	case generator.EventFileRendered, generator.EventFileWritten:
+		return JobFileEvent{
+			File:   e.File,
+			Action: string(e.Action),
+			Backup: e.Backup,
+		}
+
+	case generator.EventHookOutput:
+		return JobHookOutputEvent{Hook: e.Hook, Line: e.Message}
+	}
+
+	return JobWarningEvent{Message: e.Message}
+}
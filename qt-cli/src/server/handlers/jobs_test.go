@@ -0,0 +1,45 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package handlers
+
+import (
+	"fmt"
+	"qtcli/generator"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewJobEventData(t *testing.T) {
+	cases := []struct {
+		event    generator.Event
+		expected any
+	}{
+		{generator.Event{
+			Type:   generator.EventFileWritten,
+			File:   "/ws/a.txt",
+			Action: generator.FileActionBackedUp,
+			Backup: "/ws/a.txt.bak",
+		}, JobFileEvent{
+			File:   "/ws/a.txt",
+			Action: "backed-up",
+			Backup: "/ws/a.txt.bak",
+		}},
+		{generator.Event{
+			Type:    generator.EventHookOutput,
+			Hook:    "format",
+			Message: "done",
+		}, JobHookOutputEvent{Hook: "format", Line: "done"}},
+		{generator.Event{
+			Type:    generator.EventWarning,
+			Message: "cannot read CMakeLists.txt",
+		}, JobWarningEvent{Message: "cannot read CMakeLists.txt"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("|%s|", tc.event.Type), func(t *testing.T) {
+			assert.Equal(t, tc.expected, NewJobEventData(tc.event))
+		})
+	}
+}
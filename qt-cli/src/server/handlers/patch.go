@@ -4,6 +4,7 @@
 package handlers
 
 import (
+	"errors"
 	"qtcli/common"
 	"qtcli/runner"
 	"qtcli/util"
@@ -22,19 +23,42 @@ func PatchCustomPresetById(c *gin.Context) {
 		return
 	}
 
+	var preset common.PresetData
 	id := c.Param("id")
-	preset, err := runner.Presets.User.FindByUniqueId(id)
-	if err != nil {
-		ReplyErrorMsg(c, err.Error())
+
+	err := runner.Presets.User.GetFile().Update(
+		func(f *common.UserPresetFile) error {
+			found, err := f.FindByUniqueId(id)
+			if err != nil {
+				return err
+			}
+
+			if !matchesIfMatch(c, found) {
+				return errPresetChanged
+			}
+
+			preset = found
+			preset.Options = util.Merge(preset.GetOptions(), req.Options)
+
+			issues := common.ValidatePreset(runner.GeneratorEnv.FS, preset)
+			if issues.HasError() {
+				return common.Error{Message: common.InputHasIssues, Details: issues}
+			}
+
+			return f.Replace(preset)
+		})
+
+	if errors.Is(err, errPresetChanged) {
+		ReplyPresetChanged(c)
 		return
 	}
 
-	preset.Options = util.Merge(preset.GetOptions(), req.Options)
-
-	f := runner.Presets.User.GetFile()
-	f.Replace(preset)
-	f.Save()
+	if err != nil {
+		ReplyErrorOf(c, err)
+		return
+	}
 
+	SetPresetETag(c, preset)
 	ReplyPost(c, StatusAndIdResponse{
 		Status: common.ServerStatusUpdated,
 		Id:     preset.GetUniqueId(),
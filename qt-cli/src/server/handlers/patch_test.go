@@ -0,0 +1,89 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"path/filepath"
+	"qtcli/common"
+	"qtcli/runner"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	gin.SetMode(gin.ReleaseMode)
+}
+
+func TestHandler_PatchCustomPreset_IfMatch(t *testing.T) {
+	f := useTempUserPresets(t)
+	preset := common.NewPresetData("mine", "projects/cpp/qtquick", nil)
+	require.NoError(t, f.Update(func(f *common.UserPresetFile) error {
+		return f.Add(preset)
+	}))
+
+	id := preset.GetUniqueId()
+	read := `"` + preset.GetRevision() + `"`
+
+	w := testPatchPreset(t, id, "", "6.5")
+	ensureHttpCode(t, w, http.StatusCreated)
+	changed := w.Header().Get("ETag")
+	require.NotEqual(t, read, changed)
+
+	w = testPatchPreset(t, id, read, "6.8")
+	ensureHttpCode(t, w, http.StatusPreconditionFailed)
+	ensureResponseType[ErrorResponse](t, w)
+
+	w = testPatchPreset(t, id, "W/"+changed, "6.8")
+	ensureHttpCode(t, w, http.StatusPreconditionFailed)
+
+	w = testPatchPreset(t, id, `W/"other", `+changed, "6.8")
+	ensureHttpCode(t, w, http.StatusCreated)
+
+	saved, err := f.FindByUniqueId(id)
+	require.NoError(t, err)
+	require.Equal(t, "6.8", saved.GetOptions()["minimumQtVersion"])
+}
+
+// helpers
+func useTempUserPresets(t *testing.T) *common.UserPresetFile {
+	f := common.NewUserPresetFile(
+		path.Join(filepath.ToSlash(t.TempDir()), common.UserPresetFileName))
+	require.NoError(t, f.Open())
+
+	prev := runner.Presets.User
+	runner.Presets.User = common.NewUserPresetManager(f)
+	t.Cleanup(func() {
+		runner.Presets.User = prev
+	})
+
+	return f
+}
+
+func testPatchPreset(t *testing.T,
+	id, ifMatch, qtVersion string) *httptest.ResponseRecorder {
+	bodyBytes, err := json.Marshal(PatchCustomPresetRequest{
+		Options: map[string]any{"minimumQtVersion": qtVersion},
+	})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(
+		"PATCH", "/dont-care", bytes.NewReader(bodyBytes))
+	ctx.Params = gin.Params{{Key: "id", Value: id}}
+
+	if len(ifMatch) != 0 {
+		ctx.Request.Header.Set("If-Match", ifMatch)
+	}
+
+	PatchCustomPresetById(ctx)
+	return w
+}
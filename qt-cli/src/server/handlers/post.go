@@ -4,6 +4,7 @@
 package handlers
 
 import (
+	"errors"
 	"path/filepath"
 	"qtcli/common"
 	"qtcli/generator"
@@ -15,18 +16,40 @@ import (
 )
 
 type NewItemRequest struct {
-	Name       string         `json:"name"`
-	WorkingDir string         `json:"workingDir"`
-	PresetId   string         `json:"presetId"`
-	Options    map[string]any `json:"options"`
+	Name           string         `json:"name"`
+	WorkingDir     string         `json:"workingDir"`
+	PresetId       string         `json:"presetId"`
+	Options        map[string]any `json:"options"`
+	ConflictPolicy string         `json:"conflictPolicy"`
+	NoHooks        bool           `json:"noHooks"`
+	NoCMake        bool           `json:"noCMake"`
 }
 
 type NewItemResponse struct {
-	Type       string   `json:"type" binding:"required"`
-	Files      []string `json:"files" binding:"required"`
-	FilesDir   string   `json:"filesDir" binding:"required"`
-	WorkingDir string   `json:"workingDir" binding:"required"`
-	DryRun     bool     `json:"dryRun" binding:"required"`
+	Type       string                `json:"type" binding:"required"`
+	Files      []string              `json:"files" binding:"required"`
+	Items      []NewItemResponseFile `json:"items" binding:"required"`
+	Hooks      []NewItemResponseHook `json:"hooks" binding:"required"`
+	FilesDir   string                `json:"filesDir" binding:"required"`
+	WorkingDir string                `json:"workingDir" binding:"required"`
+	DryRun     bool                  `json:"dryRun" binding:"required"`
+}
+
+type NewItemResponseFile struct {
+	File     string `json:"file" binding:"required"`
+	Action   string `json:"action" binding:"required"`
+	Backup   string `json:"backup,omitempty"`
+	Contents string `json:"contents,omitempty"` // dry runs only
+	Diff     string `json:"diff,omitempty"`     // dry runs only
+}
+
+type NewItemResponseHook struct {
+	Name     string   `json:"name" binding:"required"`
+	Command  []string `json:"command" binding:"required"`
+	Status   string   `json:"status" binding:"required"`
+	ExitCode int      `json:"exitCode"`
+	Output   string   `json:"output"`
+	Error    string   `json:"error,omitempty"`
 }
 
 type NewCustomPresetRequest struct {
@@ -39,11 +62,28 @@ type NewCustomPresetResponse struct {
 	PresetId string `json:"presetId" binding:"required"`
 }
 
+type PresetExportRequest struct {
+	Names []string `json:"names"` // all the user presets, if empty
+}
+
+type PresetImportRequest struct {
+	Bundle common.UserPresetFileContents `json:"bundle" binding:"required"`
+	Policy string                        `json:"policy"` // fail, rename or overwrite
+}
+
+type PresetImportResponse struct {
+	Status  string                  `json:"status" binding:"required"`
+	Presets []common.ImportedPreset `json:"presets" binding:"required"`
+}
+
 type PostNewItemContext struct {
-	name       string
-	workingDir string
-	preset     common.PresetData
-	dryRun     bool
+	name           string
+	workingDir     string
+	preset         common.PresetData
+	dryRun         bool
+	conflictPolicy generator.ConflictPolicy
+	noHooks        bool
+	noCMake        bool
 }
 
 func PreparePostItemsContext(c *gin.Context) *PostNewItemContext {
@@ -59,15 +99,29 @@ func PreparePostItemsContext(c *gin.Context) *PostNewItemContext {
 		return nil
 	}
 
+	policy, err := generator.ParseConflictPolicy(req.ConflictPolicy)
+	if err != nil {
+		ReplyErrorMsg(c, err.Error())
+		return nil
+	}
+
+	if policy == generator.ConflictPrompt {
+		ReplyErrorMsg(c, common.ServerConflictPromptUnsupported)
+		return nil
+	}
+
 	preset.MergeOptions(req.Options)
 	normalizedWorkingDir := filepath.Clean(req.WorkingDir)
 	normalizedWorkingDir = filepath.ToSlash(normalizedWorkingDir)
 
 	return &PostNewItemContext{
-		name:       req.Name,
-		workingDir: normalizedWorkingDir,
-		preset:     preset,
-		dryRun:     strings.ToLower(c.Query("dry_run")) == "true",
+		name:           req.Name,
+		workingDir:     normalizedWorkingDir,
+		preset:         preset,
+		dryRun:         strings.ToLower(c.Query("dry_run")) == "true",
+		conflictPolicy: policy,
+		noHooks:        req.NoHooks,
+		noCMake:        req.NoCMake,
 	}
 }
 
@@ -77,25 +131,75 @@ func PostItems(c *gin.Context) {
 		return
 	}
 
-	result := generator.NewGenerator(context.name).
-		Env(runner.GeneratorEnv).
-		WorkingDir(context.workingDir).
-		Preset(context.preset).
-		DryRun(context.dryRun).
-		Render()
-
+	result := context.NewGenerator().Render()
 	if !result.Success {
 		ReplyError(c, result.Error.Message, &result.Error.Details)
 		return
 	}
 
-	ReplyPost(c, NewItemResponse{
-		Type:       context.preset.GetTypeName(),
-		Files:      result.Data.GetOutputFilesRel(),
-		FilesDir:   result.Data.GetOutputDirAbs(),
-		WorkingDir: context.workingDir,
-		DryRun:     context.dryRun,
-	})
+	ReplyPost(c, context.NewResponse(&result.Data))
+}
+
+// NewGenerator returns the generator of the request
+func (c *PostNewItemContext) NewGenerator() *generator.Generator {
+	return generator.NewGenerator(c.name).
+		Env(runner.GeneratorEnv).
+		WorkingDir(c.workingDir).
+		Preset(c.preset).
+		DryRun(c.dryRun).
+		ConflictPolicy(c.conflictPolicy).
+		NoHooks(c.noHooks).
+		NoCMake(c.noCMake)
+}
+
+// NewResponse describes the result of the generator of the request
+func (c *PostNewItemContext) NewResponse(
+	data *generator.ResultData) NewItemResponse {
+	return NewItemResponseFrom(data,
+		c.preset.GetTypeName(), c.workingDir, c.dryRun)
+}
+
+// NewItemResponseFrom describes the result of a generation. The contents
+// and the diffs of files are only given on dry runs.
+func NewItemResponseFrom(data *generator.ResultData,
+	typeName, workingDir string, dryRun bool) NewItemResponse {
+	items := []NewItemResponseFile{}
+	for _, f := range data.GetFiles() {
+		item := NewItemResponseFile{
+			File:   f.Output,
+			Action: string(f.Action),
+			Backup: f.Backup,
+		}
+
+		if dryRun {
+			item.Contents = f.Contents
+			item.Diff = f.Diff
+		}
+
+		items = append(items, item)
+	}
+
+	hooks := []NewItemResponseHook{}
+	for _, h := range data.GetHooks() {
+		hooks = append(hooks, NewItemResponseHook{
+			Name:     h.Name,
+			Command:  h.Command,
+			Status:   string(h.Status),
+			ExitCode: h.ExitCode,
+			Output:   h.Output,
+			Error:    h.Error,
+		})
+	}
+
+	return NewItemResponse{
+		Type:       typeName,
+		Files:      data.GetOutputFilesRel(),
+		Items:      items,
+		Hooks:      hooks,
+		FilesDir:   data.GetOutputDirAbs(),
+		WorkingDir: workingDir,
+		DryRun:     dryRun,
+	}
 }
 
 func PostItemsValidate(c *gin.Context) {
@@ -105,9 +209,11 @@ func PostItemsValidate(c *gin.Context) {
 	}
 
 	issues := generator.Validate(generator.ValidatorIn{
-		Name:       context.name,
-		WorkingDir: context.workingDir,
-		TypeId:     context.preset.GetTypeId(),
+		Name:           context.name,
+		WorkingDir:     context.workingDir,
+		TypeId:         context.preset.GetTypeId(),
+		ConflictPolicy: context.conflictPolicy,
+		DryRun:         context.dryRun,
 	})
 
 	if len(issues) != 0 {
@@ -131,25 +237,88 @@ func PostCustomPreset(c *gin.Context) {
 		return
 	}
 
-	_, err = runner.Presets.User.FindByName(req.Name)
-	if err == nil {
-		ReplyErrorMsg(c, common.ServerPresetAlreadyExists)
-		return
-	}
-
-	// TODO: validate name - ensure not starting with '@', not special chars...
 	newPreset := common.NewPresetData(
 		req.Name,
 		src.GetTemplateDir(),
 		util.Merge(src.GetOptions(), req.Options),
 	)
 
-	f := runner.Presets.User.GetFile()
-	f.Add(newPreset)
-	f.Save()
+	issues := common.ValidatePreset(runner.GeneratorEnv.FS, newPreset)
+	if issues.HasError() {
+		ReplyError(c, common.InputHasIssues, &issues)
+		return
+	}
+
+	err = runner.Presets.User.GetFile().Update(
+		func(f *common.UserPresetFile) error {
+			if f.Contains(req.Name) {
+				return errors.New(common.ServerPresetAlreadyExists)
+			}
+
+			return f.Add(newPreset)
+		})
+
+	if err != nil {
+		ReplyErrorMsg(c, err.Error())
+		return
+	}
 
+	SetPresetETag(c, newPreset)
 	ReplyPost(c, StatusAndIdResponse{
 		Status: common.ServerStatusCreated,
 		Id:     newPreset.GetUniqueId(),
 	})
 }
+
+// PostPresetsExport replies a bundle of user presets, to be imported
+// on another machine
+func PostPresetsExport(c *gin.Context) {
+	var req PresetExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ReplyErrorMsg(c, err.Error())
+		return
+	}
+
+	bundle, err := runner.Presets.User.GetFile().Export(req.Names)
+	if err != nil {
+		ReplyErrorMsg(c, err.Error())
+		return
+	}
+
+	ReplyGet(c, bundle)
+}
+
+func PostPresetsImport(c *gin.Context) {
+	var req PresetImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ReplyErrorMsg(c, err.Error())
+		return
+	}
+
+	policy, err := common.ParseImportPolicy(req.Policy)
+	if err != nil {
+		ReplyErrorMsg(c, err.Error())
+		return
+	}
+
+	var imported []common.ImportedPreset
+	err = runner.Presets.User.GetFile().Update(
+		func(f *common.UserPresetFile) error {
+			imported, err = f.Import(runner.GeneratorEnv.FS, req.Bundle, policy)
+			return err
+		})
+
+	if err != nil {
+		ReplyErrorOf(c, err)
+		return
+	}
+
+	ReplyPost(c, NewPresetImportResponse(imported))
+}
+
+func NewPresetImportResponse(imported []common.ImportedPreset) PresetImportResponse {
+	return PresetImportResponse{
+		Status:  common.ServerStatusCreated,
+		Presets: imported,
+	}
+}
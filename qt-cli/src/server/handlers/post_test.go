@@ -11,6 +11,8 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"qtcli/common"
+	"qtcli/runner"
 	"qtcli/util"
 	"strings"
 	"testing"
@@ -21,6 +23,10 @@ import (
 
 func init() {
 	gin.SetMode(gin.ReleaseMode)
+
+	// the template files of the generated projects are not kept
+	runner.GeneratorEnv.StoreDir = ""
+	runner.LoadPresets()
 }
 
 func TestHandler_PostItems(t *testing.T) {
@@ -93,8 +99,222 @@ func TestHandler_PostItems_Error_On_ExistingItem(t *testing.T) {
 	}
 }
 
+func TestHandler_PostItems_ConflictPolicy(t *testing.T) {
+	cases := []struct {
+		policy         string
+		expectedAction string
+		expectedCode   int
+	}{
+		{"", "", http.StatusBadRequest},
+		{"fail", "", http.StatusBadRequest},
+		{"prompt", "", http.StatusBadRequest},
+		{"bogus", "", http.StatusBadRequest},
+		{"skip", "skipped", http.StatusCreated},
+		{"overwrite", "overwritten", http.StatusCreated},
+		{"backup", "backed-up", http.StatusCreated},
+	}
+
+	for _, tc := range cases {
+		testname := fmt.Sprintf("|%s|%d|", tc.policy, tc.expectedCode)
+		t.Run(testname, func(t *testing.T) {
+			tempDir := createTempDir(t)
+			defer os.RemoveAll(tempDir)
+
+			existing := filepath.Join(tempDir, "myqml.qml")
+			os.WriteFile(existing, []byte("existing"), 0644)
+
+			req := NewItemRequest{
+				Name:           "myqml",
+				WorkingDir:     tempDir,
+				PresetId:       util.CreatePresetUniqueId("@types/qml"),
+				ConflictPolicy: tc.policy,
+			}
+
+			res := testNewItem(t, req, tc.expectedCode)
+			if tc.expectedCode != http.StatusCreated {
+				return
+			}
+
+			require.Len(t, res.Items, 1)
+			require.Equal(t, tc.expectedAction, res.Items[0].Action)
+
+			data, _ := os.ReadFile(existing)
+			if tc.policy == "skip" {
+				require.Equal(t, "existing", string(data))
+			} else {
+				require.NotEqual(t, "existing", string(data))
+			}
+
+			if tc.policy == "backup" {
+				data, _ = os.ReadFile(res.Items[0].Backup)
+				require.Equal(t, "existing", string(data))
+			}
+		})
+	}
+}
+
+func TestHandler_PostItems_DryRun(t *testing.T) {
+	cases := []struct {
+		existing       bool
+		policy         string
+		expectedAction string
+	}{
+		{false, "", "created"},
+		{true, "", "conflict"},
+		{true, "overwrite", "overwritten"},
+		{true, "skip", "skipped"},
+	}
+
+	for _, tc := range cases {
+		testname := fmt.Sprintf("|%v|%s|", tc.existing, tc.policy)
+		t.Run(testname, func(t *testing.T) {
+			tempDir := createTempDir(t)
+			defer os.RemoveAll(tempDir)
+
+			existing := filepath.Join(tempDir, "myqml.qml")
+			if tc.existing {
+				os.WriteFile(existing, []byte("existing\n"), 0644)
+			}
+
+			req := NewItemRequest{
+				Name:           "myqml",
+				WorkingDir:     tempDir,
+				PresetId:       util.CreatePresetUniqueId("@types/qml"),
+				ConflictPolicy: tc.policy,
+			}
+
+			res := testNewItemAt(
+				t, "/dont-care?dry_run=true", req, http.StatusCreated)
+			require.True(t, res.DryRun)
+			require.Len(t, res.Items, 1)
+
+			item := res.Items[0]
+			require.Equal(t, tc.expectedAction, item.Action)
+
+			switch tc.expectedAction {
+			case "created":
+				require.NotEmpty(t, item.Contents)
+				require.Empty(t, item.Diff)
+				require.NoFileExists(t, existing)
+
+			case "skipped":
+				require.Empty(t, item.Contents)
+				require.Empty(t, item.Diff)
+
+			default:
+				require.NotEmpty(t, item.Contents)
+				require.Contains(t, item.Diff, "--- a/myqml.qml\n")
+				require.Contains(t, item.Diff, "\n-existing\n")
+			}
+
+			if tc.existing {
+				data, _ := os.ReadFile(existing)
+				require.Equal(t, "existing\n", string(data))
+			}
+		})
+	}
+}
+
+func TestHandler_PostPresetsImport_Errors(t *testing.T) {
+	valid := common.UserPresetFileContents{Items: []common.PresetData{
+		common.NewPresetData("mine", "projects/cpp/console", nil),
+	}}
+
+	invalid := common.UserPresetFileContents{Items: []common.PresetData{
+		common.NewPresetData("mine", "projects/none", nil),
+		common.NewPresetData("other", "projects/cpp/qtquick",
+			util.StringAnyMap{"qmlRoot": "Item"}),
+	}}
+
+	cases := []struct {
+		name   string
+		req    PresetImportRequest
+		fields []string
+	}{
+		{"policy", PresetImportRequest{Bundle: valid, Policy: "merge"}, nil},
+		{"bundle", PresetImportRequest{Bundle: invalid}, []string{
+			"items[0].template", "items[1].options.qmlRoot"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			bodyBytes, err := json.Marshal(tc.req)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request = httptest.NewRequest(
+				"POST", "/dont-care", bytes.NewReader(bodyBytes))
+
+			PostPresetsImport(ctx)
+
+			ensureHttpCode(t, w, http.StatusBadRequest)
+			res := ensureResponseType[ErrorResponse](t, w)
+
+			fields := []string{}
+			if res.Details != nil {
+				for _, issue := range *res.Details {
+					fields = append(fields, issue.Field)
+				}
+			}
+
+			require.Equal(t, len(tc.fields), len(fields))
+			for i := range tc.fields {
+				require.Equal(t, tc.fields[i], fields[i])
+			}
+		})
+	}
+}
+
+func TestHandler_PostCustomPreset_InvalidOptions(t *testing.T) {
+	cases := []struct {
+		options map[string]any
+		fields  []string
+	}{
+		{map[string]any{"qqcStyle": "Material"}, []string{"options.qqcStyle"}},
+		{map[string]any{"qmlRoot": "Item"}, []string{"options.qmlRoot"}},
+		{map[string]any{"minimumQtVersion": "6.4", "qmlRoot": true},
+			[]string{"options.minimumQtVersion", "options.qmlRoot"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("|%v|", tc.options), func(t *testing.T) {
+			bodyBytes, err := json.Marshal(NewCustomPresetRequest{
+				Name:     "mypreset",
+				PresetId: util.CreatePresetUniqueId("@projects/cpp/qtquick"),
+				Options:  tc.options,
+			})
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request = httptest.NewRequest(
+				"POST", "/dont-care", bytes.NewReader(bodyBytes))
+
+			PostCustomPreset(ctx)
+
+			ensureHttpCode(t, w, http.StatusBadRequest)
+			res := ensureResponseType[ErrorResponse](t, w)
+			require.NotNil(t, res.Details)
+
+			fields := []string{}
+			for _, issue := range *res.Details {
+				fields = append(fields, issue.Field)
+			}
+
+			require.Equal(t, tc.fields, fields)
+		})
+	}
+}
+
 // helpers
-func testNewItem(t *testing.T, req NewItemRequest, expectedCode int) {
+func testNewItem(
+	t *testing.T, req NewItemRequest, expectedCode int) NewItemResponse {
+	return testNewItemAt(t, "/dont-care", req, expectedCode)
+}
+
+func testNewItemAt(t *testing.T,
+	url string, req NewItemRequest, expectedCode int) NewItemResponse {
 	bodyBytes, err := json.Marshal(req)
 	if err != nil {
 		t.Fatalf("failed to marshal request body: %v", err)
@@ -103,7 +323,7 @@ func testNewItem(t *testing.T, req NewItemRequest, expectedCode int) {
 	w := httptest.NewRecorder()
 	body := bytes.NewReader(bodyBytes)
 	ctx, _ := gin.CreateTestContext(w)
-	ctx.Request = httptest.NewRequest("POST", "/dont-care", body)
+	ctx.Request = httptest.NewRequest("POST", url, body)
 
 	PostItems(ctx)
 
@@ -112,7 +332,10 @@ func testNewItem(t *testing.T, req NewItemRequest, expectedCode int) {
 		res := ensureResponseType[NewItemResponse](t, w)
 		require.Equal(t, filepath.ToSlash(req.WorkingDir), res.WorkingDir)
 		require.NotEmpty(t, res.Files)
+		return res
 	}
+
+	return NewItemResponse{}
 }
 
 func createTempDir(t *testing.T) string {
@@ -0,0 +1,170 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"qtcli/util"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var instanceNameRegex = regexp.MustCompile(`^[A-Za-z0-9_-]*$`)
+
+// lockPollInterval is how often a starting server checks whether the
+// one it stops released the pid file
+const lockPollInterval = 50 * time.Millisecond
+
+// instance tells where the files of a server are: its pid file, its
+// token file and its socket. Servers with different names can run side
+// by side; the default instance has no name.
+type instance struct {
+	name string
+	dir  string
+}
+
+// ValidateInstanceName fails for names which cannot be part of a file name
+func ValidateInstanceName(name string) error {
+	if len(name) > 64 || !instanceNameRegex.MatchString(name) {
+		return fmt.Errorf(util.Msg(
+			"invalid instance name, use letters, digits, '-' and '_', given = '%v'"),
+			name)
+	}
+
+	return nil
+}
+
+func newInstance(name string) (instance, error) {
+	if err := ValidateInstanceName(name); err != nil {
+		return instance{}, err
+	}
+
+	dir, err := util.RuntimeDir()
+	if err != nil {
+		return instance{}, err
+	}
+
+	return instance{name: name, dir: dir}, nil
+}
+
+func (i instance) baseName() string {
+	if len(i.name) == 0 {
+		return "qtcli-server"
+	}
+
+	return "qtcli-server-" + i.name
+}
+
+func (i instance) filePath(ext string) string {
+	return filepath.Join(i.dir, i.baseName()+ext)
+}
+
+func (i instance) pidFile() string {
+	return i.filePath(".pid")
+}
+
+func (i instance) tokenFile() string {
+	return i.filePath(".token")
+}
+
+// lockRunning locks the pid file for as long as the server runs, which
+// tells the other processes that the pid in it is the one of a live
+// server. A running server of the same instance is stopped first, and
+// keeps the lock until the requests and the jobs it lets finish are done.
+func (i instance) lockRunning() (*util.FileLock, error) {
+	deadline := time.Now().Add(shutdownTimeout + util.FileLockTimeout)
+	stopped := false
+
+	for {
+		lock, err := util.TryLockFile(i.pidFile())
+		if !errors.Is(err, util.ErrFileLocked) {
+			return lock, err
+		}
+
+		// a starting server holds the lock before it writes its pid
+		if !stopped {
+			if pid, ok := i.lockHolderPid(); ok {
+				util.SendSigTermOrKill(pid)
+				stopped = true
+			}
+		}
+
+		if !time.Now().Before(deadline) {
+			return nil, err
+		}
+
+		time.Sleep(lockPollInterval)
+	}
+}
+
+// runningPid returns the pid of the server of the instance,
+// or false if it does not run
+func (i instance) runningPid() (int, bool) {
+	lock, err := util.TryLockFile(i.pidFile())
+	if err == nil {
+		lock.Unlock()
+		return 0, false
+	}
+
+	if !errors.Is(err, util.ErrFileLocked) {
+		return 0, false
+	}
+
+	return i.lockHolderPid()
+}
+
+// lockHolderPid returns the pid in the pid file, if it is the one of the
+// process holding the lock. The file may be left by a server which did
+// not stop cleanly, and its pid reused by an unrelated process, so the
+// start time of the process must be the one written with the pid.
+func (i instance) lockHolderPid() (int, bool) {
+	pid, startTime, err := readPidFile(i.pidFile())
+	if err != nil {
+		return 0, false
+	}
+
+	actual, err := util.ProcessStartTime(pid)
+	if errors.Is(err, errors.ErrUnsupported) {
+		return pid, util.ProcessExists(pid)
+	}
+
+	return pid, err == nil && actual == startTime
+}
+
+// pidFileContents returns the pid of the current process and its start
+// time, as read by readPidFile
+func pidFileContents() string {
+	pid := os.Getpid()
+	startTime, err := util.ProcessStartTime(pid)
+	if err != nil {
+		return strconv.Itoa(pid)
+	}
+
+	return fmt.Sprintf("%d %d", pid, startTime)
+}
+
+func readPidFile(filePath string) (int, int64, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, 0, errors.New(util.Msg("empty pid file"))
+	}
+
+	pid, err := strconv.Atoi(fields[0])
+	if err != nil || len(fields) == 1 {
+		return pid, 0, err
+	}
+
+	startTime, err := strconv.ParseInt(fields[1], 10, 64)
+	return pid, startTime, err
+}
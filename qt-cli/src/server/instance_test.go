@@ -0,0 +1,46 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package server
+
+import (
+	"fmt"
+	"os"
+	"qtcli/util"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstance_LockHolderPid(t *testing.T) {
+	startTime, err := util.ProcessStartTime(os.Getpid())
+	if err != nil {
+		t.Skip(err)
+	}
+
+	all := []struct {
+		contents string
+		expected bool
+	}{
+		{pidFileContents(), true},
+		{fmt.Sprintf("%d %d", os.Getpid(), startTime+1), false},
+		{fmt.Sprintf("%d", os.Getpid()), false},
+		{"", false},
+		{"pid", false},
+	}
+
+	for _, tc := range all {
+		t.Run(fmt.Sprintf("|%v|", tc.contents), func(t *testing.T) {
+			inst := instance{name: "test", dir: t.TempDir()}
+			require.NoError(t, os.WriteFile(
+				inst.pidFile(), []byte(tc.contents), 0644))
+
+			pid, ok := inst.lockHolderPid()
+			assert.Equal(t, tc.expected, ok)
+			if ok {
+				assert.Equal(t, os.Getpid(), pid)
+			}
+		})
+	}
+}
@@ -0,0 +1,303 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"qtcli/common"
+	"qtcli/generator"
+	"qtcli/server/handlers"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+// how long the events of a finished job can still be read
+const jobKeepTime = 10 * time.Minute
+
+// jobEvent is numbered from zero, in the order of the job
+type jobEvent struct {
+	id   int
+	name string
+	data any
+}
+
+// job is a generation running in the background. Its events are kept,
+// so that clients joining late, or reconnecting, read all of them.
+type job struct {
+	id     string
+	cancel context.CancelFunc
+
+	mutex      sync.Mutex
+	events     []jobEvent
+	changed    chan struct{} // closed on each new event
+	done       chan struct{} // closed once finished
+	finished   bool
+	finishedAt time.Time
+}
+
+func (j *job) add(name string, data any) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if !j.finished {
+		j.appendLocked(name, data)
+		j.changed = make(chan struct{})
+	}
+}
+
+// finish adds the last event, done or error. It is added and the job
+// marked finished at once, so that a stream reading the last event also
+// sees the job finished and does not wait for another one.
+func (j *job) finish(name string, data any) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if j.finished {
+		return
+	}
+
+	j.appendLocked(name, data)
+	j.finished = true
+	j.finishedAt = time.Now()
+	close(j.done)
+}
+
+// appendLocked adds an event and wakes up the streams waiting for it;
+// the mutex must be held
+func (j *job) appendLocked(name string, data any) {
+	j.events = append(j.events, jobEvent{len(j.events), name, data})
+	close(j.changed)
+}
+
+// since returns the events from the given id on, a channel closed
+// on the next event, and whether the job is finished
+func (j *job) since(id int) ([]jobEvent, <-chan struct{}, bool) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if id >= len(j.events) {
+		return nil, j.changed, j.finished
+	}
+
+	return j.events[max(id, 0):], j.changed, j.finished
+}
+
+func (j *job) isFinished() bool {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	return j.finished
+}
+
+func (j *job) expired(now time.Time) bool {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	return j.finished && now.Sub(j.finishedAt) > jobKeepTime
+}
+
+type jobRegistry struct {
+	mutex sync.Mutex
+	jobs  map[string]*job
+}
+
+var jobs = &jobRegistry{jobs: map[string]*job{}}
+
+// start runs the generation of the request in the background
+func (r *jobRegistry) start(req *handlers.PostNewItemContext) (*job, error) {
+	id, err := newJobId()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	j := &job{
+		id:      id,
+		cancel:  cancel,
+		changed: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	r.mutex.Lock()
+	r.prune()
+	r.jobs[id] = j
+	r.mutex.Unlock()
+
+	serverActivity.begin()
+	go func() {
+		defer serverActivity.end()
+		defer cancel()
+
+		result := req.NewGenerator().
+			Context(ctx).
+			Events(func(e generator.Event) {
+				j.add(string(e.Type), handlers.NewJobEventData(e))
+			}).
+			Render()
+
+		if !result.Success {
+			j.finish(handlers.JobEventError, handlers.NewErrorResponse(
+				result.Error.Message, &result.Error.Details))
+			return
+		}
+
+		j.finish(handlers.JobEventDone, req.NewResponse(&result.Data))
+	}()
+
+	return j, nil
+}
+
+func (r *jobRegistry) get(id string) (*job, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	j, ok := r.jobs[id]
+	return j, ok
+}
+
+// wait waits for the running jobs to finish, or for ctx to be done
+func (r *jobRegistry) wait(ctx context.Context) {
+	for {
+		j, ok := r.anyRunning()
+		if !ok {
+			return
+		}
+
+		select {
+		case <-j.done:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *jobRegistry) anyRunning() (*job, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, j := range r.jobs {
+		if !j.isFinished() {
+			return j, true
+		}
+	}
+
+	return nil, false
+}
+
+func (r *jobRegistry) cancelAll() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, j := range r.jobs {
+		j.cancel()
+	}
+}
+
+// prune forgets the jobs finished for long; the registry must be locked
+func (r *jobRegistry) prune() {
+	now := time.Now()
+	for id, j := range r.jobs {
+		if j.expired(now) {
+			delete(r.jobs, id)
+		}
+	}
+}
+
+func newJobId() (string, error) {
+	raw := make([]byte, 8)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(raw), nil
+}
+
+// postJob starts creating an item, like POST /items, and replies
+// the id of the job at once
+func postJob(c *gin.Context) {
+	req := handlers.PreparePostItemsContext(c)
+	if req == nil {
+		return
+	}
+
+	j, err := jobs.start(req)
+	if err != nil {
+		handlers.ReplyErrorMsg(c, err.Error())
+		return
+	}
+
+	handlers.ReplyPost(c, handlers.StatusAndIdResponse{
+		Status: common.ServerStatusCreated,
+		Id:     j.id,
+	})
+}
+
+// getJobEvents streams the events of a job until it is finished.
+// A client reconnecting with Last-Event-ID reads the events it missed.
+func getJobEvents(c *gin.Context) {
+	j, ok := jobs.get(c.Param("id"))
+	if !ok {
+		handlers.ReplyErrorMsg(c, common.ServerNoJob)
+		return
+	}
+
+	next := 0
+	if last, err := strconv.Atoi(c.GetHeader("Last-Event-ID")); err == nil {
+		next = last + 1
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Stream(func(w io.Writer) bool {
+		events, changed, finished := j.since(next)
+		if len(events) == 0 {
+			if finished {
+				return false
+			}
+
+			select {
+			case <-changed:
+			case <-c.Request.Context().Done():
+			}
+
+			return true
+		}
+
+		for _, e := range events {
+			c.Render(-1, sse.Event{
+				Id:    strconv.Itoa(e.id),
+				Event: e.name,
+				Data:  e.data,
+			})
+
+			next = e.id + 1
+		}
+
+		return !finished
+	})
+}
+
+// deleteJob cancels a running job. Files are not written if it is
+// cancelled in time, and hooks not yet run are skipped.
+func deleteJob(c *gin.Context) {
+	j, ok := jobs.get(c.Param("id"))
+	if !ok {
+		handlers.ReplyErrorMsg(c, common.ServerNoJob)
+		return
+	}
+
+	if j.isFinished() {
+		handlers.ReplyErrorMsg(c, common.ServerJobFinished)
+		return
+	}
+
+	j.cancel()
+	handlers.ReplyStatus(c, common.StatusCancelled)
+}
@@ -0,0 +1,94 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestJob(t *testing.T, id string) *job {
+	_, cancel := context.WithCancel(context.Background())
+	j := &job{
+		id:      id,
+		cancel:  cancel,
+		changed: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	jobs.mutex.Lock()
+	jobs.jobs[id] = j
+	jobs.mutex.Unlock()
+
+	t.Cleanup(func() {
+		jobs.mutex.Lock()
+		delete(jobs.jobs, id)
+		jobs.mutex.Unlock()
+	})
+
+	return j
+}
+
+func TestJob_FinishWhileStreaming(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+	r.GET("/jobs/:id/events", getJobEvents)
+
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	for i := 0; i < 200; i++ {
+		id := fmt.Sprintf("job-%d", i)
+		j := newTestJob(t, id)
+
+		go func() {
+			j.add("file-written", "a")
+			j.finish("done", "b")
+		}()
+
+		// the stream ends once the job is finished, with the last event
+		ended := make(chan string, 1)
+		go func() {
+			res, err := http.Get(server.URL + "/jobs/" + id + "/events")
+			if err != nil {
+				ended <- err.Error()
+				return
+			}
+
+			defer res.Body.Close()
+			body, _ := io.ReadAll(res.Body)
+			ended <- string(body)
+		}()
+
+		select {
+		case body := <-ended:
+			require.True(t, strings.Contains(body, "event:done"), body)
+			assert.Equal(t, 1, strings.Count(body, "event:file-written"))
+		case <-time.After(5 * time.Second):
+			t.Fatalf("the stream of the job did not end, id = %v", id)
+		}
+	}
+}
+
+func TestJob_AddAfterFinish(t *testing.T) {
+	j := newTestJob(t, "finished")
+	j.finish("done", nil)
+	j.add("warning", nil)
+	j.finish("error", nil)
+
+	events, _, finished := j.since(0)
+	assert.True(t, finished)
+	require.Len(t, events, 1)
+	assert.Equal(t, "done", events[0].name)
+}
@@ -0,0 +1,87 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package server
+
+import (
+	"fmt"
+	"qtcli/util"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// how long the requests being served and the jobs running can take
+// to finish, once the server is asked to stop
+const shutdownTimeout = 30 * time.Second
+
+// how often the idle time and the parent process are checked
+const watchInterval = 5 * time.Second
+
+// activity tells how long the server has been idle, that is without
+// requests being served or jobs running
+type activity struct {
+	mutex  sync.Mutex
+	active int
+	last   time.Time
+}
+
+var serverActivity = &activity{last: time.Now()}
+
+func (a *activity) begin() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.active++
+}
+
+func (a *activity) end() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.active--
+	a.last = time.Now()
+}
+
+func (a *activity) idleFor(now time.Time) time.Duration {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.active != 0 {
+		return 0
+	}
+
+	return now.Sub(a.last)
+}
+
+// track is a middleware counting the request as activity
+func (a *activity) track(c *gin.Context) {
+	a.begin()
+	defer a.end()
+
+	c.Next()
+}
+
+// watch stops the server once it has been idle for the timeout of the
+// options, or once the parent process given in the options is gone
+func watch(o Options, stop func(reason string)) {
+	if o.IdleTimeout <= 0 && o.ParentPid <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		if o.IdleTimeout > 0 && serverActivity.idleFor(now) >= o.IdleTimeout {
+			stop(fmt.Sprintf("idle for %v", o.IdleTimeout))
+			return
+		}
+
+		if o.ParentPid > 0 && !util.ProcessExists(o.ParentPid) {
+			stop(fmt.Sprintf("parent process %d is gone", o.ParentPid))
+			return
+		}
+	}
+}
@@ -0,0 +1,26 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActivity_IdleFor(t *testing.T) {
+	start := time.Now()
+	a := &activity{last: start}
+	assert.Equal(t, time.Minute, a.idleFor(start.Add(time.Minute)))
+
+	a.begin()
+	a.begin()
+	a.end()
+	assert.Zero(t, a.idleFor(time.Now().Add(time.Hour)))
+
+	a.end()
+	assert.InDelta(t, time.Hour, a.idleFor(time.Now().Add(time.Hour)),
+		float64(time.Second))
+}
@@ -0,0 +1,352 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package server
+
+import (
+	"cmp"
+	"net/http"
+	"qtcli/common"
+	"qtcli/runner"
+	"qtcli/server/handlers"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// apiOperation documents a route of createApiHandler, which the
+// contract test keeps in line with the routes registered there
+type apiOperation struct {
+	method   string
+	path     string // relative to /v1, in the syntax of gin
+	summary  string
+	query    []apiParam
+	header   []apiParam
+	request  any // type of the JSON body, if any
+	status   int
+	response any  // type of the JSON reply, or apiOneOf
+	stream   bool // server-sent events instead of JSON
+	errors   []int
+}
+
+type apiParam struct {
+	name        string
+	description string
+}
+
+var dryRunParam = apiParam{"dry_run",
+	"'true' to render the files without writing them"}
+
+var apiOperations = []apiOperation{
+	{
+		method:  http.MethodGet,
+		path:    "/presets",
+		summary: "List the presets, or find one by name",
+		query: []apiParam{
+			{"name", "Name of the preset, replying its details"},
+			{"type", "Type of the presets: project or file"},
+		},
+		status: http.StatusOK,
+		response: apiOneOf{
+			handlers.PresetsResponse{},
+			handlers.PresetDetailResponse{},
+		},
+	},
+	{
+		method:   http.MethodGet,
+		path:     "/presets/:id",
+		summary:  "Read a preset, with its ETag",
+		status:   http.StatusOK,
+		response: handlers.PresetDetailResponse{},
+	},
+	{
+		method:   http.MethodPost,
+		path:     "/presets",
+		summary:  "Add a user preset based on another one",
+		request:  handlers.NewCustomPresetRequest{},
+		status:   http.StatusCreated,
+		response: handlers.StatusAndIdResponse{},
+	},
+	{
+		method:  http.MethodPatch,
+		path:    "/presets/:id",
+		summary: "Change the options of a user preset",
+		header: []apiParam{
+			{"If-Match", "ETag of the preset when it was read"},
+		},
+		request:  handlers.PatchCustomPresetRequest{},
+		status:   http.StatusCreated,
+		response: handlers.StatusAndIdResponse{},
+		errors:   []int{http.StatusPreconditionFailed},
+	},
+	{
+		method:  http.MethodDelete,
+		path:    "/presets/:id",
+		summary: "Remove a user preset",
+		header: []apiParam{
+			{"If-Match", "ETag of the preset when it was read"},
+		},
+		status:   http.StatusOK,
+		response: handlers.PresetDeleteResponse{},
+		errors:   []int{http.StatusPreconditionFailed},
+	},
+	{
+		method:   http.MethodPost,
+		path:     "/presets/export",
+		summary:  "Write user presets to a bundle",
+		request:  handlers.PresetExportRequest{},
+		status:   http.StatusOK,
+		response: common.UserPresetFileContents{},
+	},
+	{
+		method:   http.MethodPost,
+		path:     "/presets/import",
+		summary:  "Add the presets of a bundle",
+		request:  handlers.PresetImportRequest{},
+		status:   http.StatusCreated,
+		response: handlers.PresetImportResponse{},
+	},
+	{
+		method:   http.MethodPost,
+		path:     "/items",
+		summary:  "Create a project or a file",
+		query:    []apiParam{dryRunParam},
+		request:  handlers.NewItemRequest{},
+		status:   http.StatusCreated,
+		response: handlers.NewItemResponse{},
+	},
+	{
+		method:   http.MethodPost,
+		path:     "/items/validate",
+		summary:  "Check the input of a creation",
+		query:    []apiParam{dryRunParam},
+		request:  handlers.NewItemRequest{},
+		status:   http.StatusOK,
+		response: handlers.StatusResponse{},
+	},
+	{
+		method:   http.MethodPost,
+		path:     "/jobs",
+		summary:  "Create a project or a file in the background",
+		query:    []apiParam{dryRunParam},
+		request:  handlers.NewItemRequest{},
+		status:   http.StatusCreated,
+		response: handlers.StatusAndIdResponse{},
+	},
+	{
+		method: http.MethodGet,
+		path:   "/jobs/:id/events",
+		summary: "Follow a job: file-rendered, file-written, warning and " +
+			"hook-output events, then done or error",
+		header: []apiParam{
+			{"Last-Event-ID", "Id of the last event read, to read the next ones"},
+		},
+		status: http.StatusOK,
+		stream: true,
+	},
+	{
+		method:   http.MethodDelete,
+		path:     "/jobs/:id",
+		summary:  "Cancel a running job",
+		status:   http.StatusOK,
+		response: handlers.StatusResponse{},
+	},
+	{
+		method:   http.MethodGet,
+		path:     "/ready",
+		summary:  "Tell if the server is ready",
+		status:   http.StatusOK,
+		response: handlers.StatusResponse{},
+	},
+	{
+		method:   http.MethodDelete,
+		path:     "/server",
+		summary:  "Stop the server",
+		status:   http.StatusOK,
+		response: handlers.StatusResponse{},
+	},
+	{
+		method:   http.MethodGet,
+		path:     "/openapi.json",
+		summary:  "Read this document",
+		status:   http.StatusOK,
+		response: map[string]any{},
+	},
+}
+
+// the data of the events of jobs, described for clients generating types
+var jobEventTypes = []any{
+	handlers.JobFileEvent{},
+	handlers.JobWarningEvent{},
+	handlers.JobHookOutputEvent{},
+}
+
+type openApiDocument struct {
+	OpenApi    string                                 `json:"openapi"`
+	Info       openApiInfo                            `json:"info"`
+	Servers    []openApiServer                        `json:"servers"`
+	Security   []map[string][]string                  `json:"security"`
+	Paths      map[string]map[string]openApiOperation `json:"paths"`
+	Components openApiComponents                      `json:"components"`
+}
+
+type openApiInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openApiServer struct {
+	Url string `json:"url"`
+}
+
+type openApiOperation struct {
+	Summary     string                     `json:"summary"`
+	Parameters  []openApiParameter         `json:"parameters,omitempty"`
+	RequestBody *openApiBody               `json:"requestBody,omitempty"`
+	Responses   map[string]openApiResponse `json:"responses"`
+}
+
+type openApiParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *openApiSchema `json:"schema"`
+}
+
+type openApiBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]openApiMediaType `json:"content"`
+}
+
+type openApiResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openApiMediaType `json:"content,omitempty"`
+}
+
+type openApiMediaType struct {
+	Schema *openApiSchema `json:"schema"`
+}
+
+type openApiComponents struct {
+	Schemas         map[string]*openApiSchema        `json:"schemas"`
+	SecuritySchemes map[string]openApiSecurityScheme `json:"securitySchemes"`
+}
+
+type openApiSecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme"`
+}
+
+// newOpenApiDocument describes apiOperations, with the schemas of the
+// handler types. All the operations take the session token, and reply
+// an ErrorResponse on errors.
+func newOpenApiDocument() openApiDocument {
+	b := newSchemaBuilder()
+	errorSchema := b.of(handlers.ErrorResponse{})
+	for _, t := range jobEventTypes {
+		b.of(t)
+	}
+
+	paths := map[string]map[string]openApiOperation{}
+	for _, op := range apiOperations {
+		path, params := openApiPath(op.path)
+		if paths[path] == nil {
+			paths[path] = map[string]openApiOperation{}
+		}
+
+		for _, p := range op.query {
+			params = append(params, openApiParameter{Name: p.name,
+				In: "query", Description: p.description,
+				Schema: &openApiSchema{Type: "string"}})
+		}
+
+		for _, p := range op.header {
+			params = append(params, openApiParameter{Name: p.name,
+				In: "header", Description: p.description,
+				Schema: &openApiSchema{Type: "string"}})
+		}
+
+		o := openApiOperation{
+			Summary:    op.summary,
+			Parameters: params,
+			Responses:  map[string]openApiResponse{},
+		}
+
+		if op.request != nil {
+			o.RequestBody = &openApiBody{
+				Required: true,
+				Content:  jsonContent(b.of(op.request)),
+			}
+		}
+
+		success := openApiResponse{Description: http.StatusText(op.status)}
+		if op.stream {
+			success.Content = map[string]openApiMediaType{
+				"text/event-stream": {Schema: &openApiSchema{Type: "string"}},
+			}
+		} else {
+			success.Content = jsonContent(b.of(op.response))
+		}
+
+		o.Responses[strconv.Itoa(op.status)] = success
+
+		codes := append([]int{http.StatusBadRequest, http.StatusUnauthorized},
+			op.errors...)
+		for _, code := range codes {
+			o.Responses[strconv.Itoa(code)] = openApiResponse{
+				Description: http.StatusText(code),
+				Content:     jsonContent(errorSchema),
+			}
+		}
+
+		paths[path][strings.ToLower(op.method)] = o
+	}
+
+	return openApiDocument{
+		OpenApi: "3.0.3",
+		Info: openApiInfo{
+			Title:   "qtcli REST API",
+			Version: cmp.Or(runner.GeneratorEnv.Version, "dev"),
+		},
+		Servers:  []openApiServer{{Url: "/v1"}},
+		Security: []map[string][]string{{"sessionToken": {}}},
+		Paths:    paths,
+		Components: openApiComponents{
+			Schemas: b.components,
+			SecuritySchemes: map[string]openApiSecurityScheme{
+				"sessionToken": {Type: "http", Scheme: "bearer"},
+			},
+		},
+	}
+}
+
+// openApiPath turns a path of gin, like /presets/:id, into the one of
+// OpenAPI, like /presets/{id}, with its parameters
+func openApiPath(path string) (string, []openApiParameter) {
+	params := []openApiParameter{}
+	parts := strings.Split(path, "/")
+
+	for i, part := range parts {
+		if name, ok := strings.CutPrefix(part, ":"); ok {
+			parts[i] = "{" + name + "}"
+			params = append(params, openApiParameter{Name: name,
+				In: "path", Required: true,
+				Schema: &openApiSchema{Type: "string"}})
+		}
+	}
+
+	return strings.Join(parts, "/"), params
+}
+
+func jsonContent(s *openApiSchema) map[string]openApiMediaType {
+	return map[string]openApiMediaType{"application/json": {Schema: s}}
+}
+
+var openApi = sync.OnceValue(newOpenApiDocument)
+
+func getOpenApi(c *gin.Context) {
+	handlers.ReplyGet(c, openApi())
+}
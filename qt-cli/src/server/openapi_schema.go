@@ -0,0 +1,139 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package server
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// openApiSchema is the subset of JSON schema used by the document
+type openApiSchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Items                *openApiSchema            `json:"items,omitempty"`
+	Properties           map[string]*openApiSchema `json:"properties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	AdditionalProperties *openApiSchema            `json:"additionalProperties,omitempty"`
+	OneOf                []*openApiSchema          `json:"oneOf,omitempty"`
+}
+
+// apiOneOf is a value of one of the given types
+type apiOneOf []any
+
+// schemaBuilder describes Go types the way encoding/json writes them.
+// Named structs and slices become components, referred to by their name.
+type schemaBuilder struct {
+	components map[string]*openApiSchema
+}
+
+func newSchemaBuilder() *schemaBuilder {
+	return &schemaBuilder{components: map[string]*openApiSchema{}}
+}
+
+func (b *schemaBuilder) of(v any) *openApiSchema {
+	if all, ok := v.(apiOneOf); ok {
+		s := &openApiSchema{}
+		for _, one := range all {
+			s.OneOf = append(s.OneOf, b.of(one))
+		}
+
+		return s
+	}
+
+	return b.ofType(reflect.TypeOf(v))
+}
+
+func (b *schemaBuilder) ofType(t reflect.Type) *openApiSchema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &openApiSchema{Type: "boolean"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &openApiSchema{Type: "integer"}
+
+	case reflect.Float32, reflect.Float64:
+		return &openApiSchema{Type: "number"}
+
+	case reflect.String:
+		return &openApiSchema{Type: "string"}
+
+	case reflect.Map:
+		return &openApiSchema{
+			Type:                 "object",
+			AdditionalProperties: b.ofType(t.Elem()),
+		}
+
+	case reflect.Slice, reflect.Array:
+		return b.component(t, func() *openApiSchema {
+			return &openApiSchema{Type: "array", Items: b.ofType(t.Elem())}
+		})
+
+	case reflect.Struct:
+		return b.component(t, func() *openApiSchema {
+			s := &openApiSchema{
+				Type:       "object",
+				Properties: map[string]*openApiSchema{},
+			}
+
+			b.addFields(s, t)
+			return s
+		})
+	}
+
+	// interfaces, like any
+	return &openApiSchema{}
+}
+
+// component refers to the schema of a named type, built once
+func (b *schemaBuilder) component(
+	t reflect.Type, build func() *openApiSchema) *openApiSchema {
+	if len(t.Name()) == 0 {
+		return build()
+	}
+
+	ref := &openApiSchema{Ref: "#/components/schemas/" + t.Name()}
+	if _, done := b.components[t.Name()]; !done {
+		b.components[t.Name()] = &openApiSchema{} // for recursive types
+		b.components[t.Name()] = build()
+	}
+
+	return ref
+}
+
+// addFields adds the exported fields of t, and of the structs it embeds.
+// Fields bound with 'required' are required.
+func (b *schemaBuilder) addFields(s *openApiSchema, t reflect.Type) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+
+		if f.Anonymous && len(name) == 0 && f.Type.Kind() == reflect.Struct {
+			b.addFields(s, f.Type)
+			continue
+		}
+
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+
+		if len(name) == 0 {
+			name = f.Name
+		}
+
+		s.Properties[name] = b.ofType(f.Type)
+
+		binding := strings.Split(f.Tag.Get("binding"), ",")
+		if slices.Contains(binding, "required") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
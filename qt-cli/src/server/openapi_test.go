@@ -0,0 +1,203 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"qtcli/runner"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	runner.LoadPresets()
+}
+
+func TestOpenApi_CoversRoutes(t *testing.T) {
+	routes := []string{}
+	for _, r := range createApiHandler("secret").Routes() {
+		path, ok := strings.CutPrefix(r.Path, "/v1")
+		require.True(t, ok, r.Path)
+		routes = append(routes, r.Method+" "+path)
+	}
+
+	documented := []string{}
+	for _, op := range apiOperations {
+		documented = append(documented, op.method+" "+op.path)
+	}
+
+	assert.ElementsMatch(t, routes, documented)
+}
+
+func TestOpenApi_Document(t *testing.T) {
+	doc := newOpenApiDocument()
+
+	refs := []string{}
+	var walk func(s *openApiSchema)
+	walk = func(s *openApiSchema) {
+		if s == nil {
+			return
+		}
+
+		if len(s.Ref) != 0 {
+			refs = append(refs, s.Ref)
+		}
+
+		walk(s.Items)
+		walk(s.AdditionalProperties)
+		for _, p := range s.Properties {
+			walk(p)
+		}
+
+		for _, one := range s.OneOf {
+			walk(one)
+		}
+	}
+
+	for _, s := range doc.Components.Schemas {
+		walk(s)
+	}
+
+	for path, ops := range doc.Paths {
+		for method, op := range ops {
+			assert.NotEmpty(t, op.Summary, "%s %s", method, path)
+			assert.Contains(t, op.Responses, "401", "%s %s", method, path)
+
+			if op.RequestBody != nil {
+				walk(op.RequestBody.Content["application/json"].Schema)
+			}
+
+			for _, r := range op.Responses {
+				for _, content := range r.Content {
+					walk(content.Schema)
+				}
+			}
+		}
+	}
+
+	for _, ref := range refs {
+		name, _ := strings.CutPrefix(ref, "#/components/schemas/")
+		assert.Contains(t, doc.Components.Schemas, name)
+	}
+
+	item := doc.Components.Schemas["NewItemResponseFile"]
+	assert.Equal(t, []string{"file", "action"}, item.Required)
+	assert.Equal(t, "string", item.Properties["backup"].Type)
+}
+
+func TestOpenApi_Replies(t *testing.T) {
+	consoleId := ""
+	for _, p := range runner.Presets.Default.GetAll() {
+		if p.Name == "@projects/cpp/console" {
+			consoleId = p.GetUniqueId()
+		}
+	}
+
+	paths := []struct {
+		url  string
+		spec string
+	}{
+		{"/v1/ready", "/ready"},
+		{"/v1/presets", "/presets"},
+		{"/v1/presets?name=@projects/cpp/console", "/presets"},
+		{"/v1/presets/" + consoleId, "/presets/{id}"},
+		{"/v1/openapi.json", "/openapi.json"},
+	}
+
+	r := createApiHandler("secret")
+	doc := newOpenApiDocument()
+
+	for _, tc := range paths {
+		t.Run(fmt.Sprintf("|%s|", tc.url), func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", tc.url, nil)
+			req.Header.Set("Authorization", "Bearer secret")
+			r.ServeHTTP(w, req)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+			var value any
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &value))
+
+			schema := doc.Paths[tc.spec]["get"].
+				Responses["200"].Content["application/json"].Schema
+			assert.NoError(t, matchSchema(doc, schema, value, "reply"))
+		})
+	}
+}
+
+// matchSchema checks that a value decoded from JSON has the properties
+// of the schema, and only those
+func matchSchema(doc openApiDocument, s *openApiSchema,
+	value any, at string) error {
+	if name, ok := strings.CutPrefix(s.Ref, "#/components/schemas/"); ok {
+		return matchSchema(doc, doc.Components.Schemas[name], value, at)
+	}
+
+	if len(s.OneOf) != 0 {
+		for _, one := range s.OneOf {
+			if matchSchema(doc, one, value, at) == nil {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("%s matches no schema", at)
+	}
+
+	if len(s.Type) == 0 {
+		return nil // any value
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		if s.Type != "object" {
+			return fmt.Errorf("%s is an object, not %q", at, s.Type)
+		}
+
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				return fmt.Errorf("%s.%s is missing", at, name)
+			}
+		}
+
+		for name, field := range v {
+			p := s.Properties[name]
+			if p == nil {
+				p = s.AdditionalProperties
+			}
+
+			if p == nil {
+				return fmt.Errorf("%s.%s is not documented", at, name)
+			}
+
+			if err := matchSchema(doc, p, field, at+"."+name); err != nil {
+				return err
+			}
+		}
+
+	case []any:
+		if s.Type != "array" {
+			return fmt.Errorf("%s is an array, not %q", at, s.Type)
+		}
+
+		for i, item := range v {
+			err := matchSchema(doc, s.Items, item, fmt.Sprintf("%s[%d]", at, i))
+			if err != nil {
+				return err
+			}
+		}
+
+	case string:
+		if s.Type != "string" {
+			return fmt.Errorf("%s is a string, not %q", at, s.Type)
+		}
+	}
+
+	return nil
+}
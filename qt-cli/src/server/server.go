@@ -4,6 +4,9 @@
 package server
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"net"
 	"net/http"
@@ -12,7 +15,6 @@ import (
 	"path/filepath"
 	"qtcli/server/handlers"
 	"qtcli/util"
-	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -25,76 +27,165 @@ import (
 type Options struct {
 	UseTcp  bool
 	TcpPort string
-}
 
-var pidFile = getPidFilePath()
+	// TCP address to listen on, like '0.0.0.0:8080', instead of
+	// the loopback interface; implies UseTcp
+	ListenAddr string
+
+	// name of the instance, for several servers to run side by side
+	Name string
+
+	// time without requests after which the server stops, if not zero
+	IdleTimeout time.Duration
+
+	// pid of the process the server stops with, if not zero
+	ParentPid int
+}
 
 func init() {
 	gin.SetMode(gin.ReleaseMode)
 }
 
-func getNetListener(o Options) (net.Listener, error) {
+func getNetListener(o Options, i instance) (net.Listener, error) {
+	if len(o.ListenAddr) != 0 {
+		return net.Listen("tcp", o.ListenAddr)
+	}
+
 	if o.UseTcp {
 		if o.TcpPort == "" {
 			o.TcpPort = "8080"
 		}
 
-		return net.Listen("tcp", ":"+o.TcpPort)
+		return net.Listen("tcp", net.JoinHostPort("127.0.0.1", o.TcpPort))
 	}
 
-	return getLocalIpcListener()
+	return getLocalIpcListener(i)
 }
 
-func Start(o Options) {
-	ensurePrevRunStopped(pidFile)
+// Start serves the API until the server is asked to stop. The pid and
+// token files exist before the listener does, so that a client finding
+// the server can always read the token of it.
+func Start(o Options) error {
+	inst, err := newInstance(o.Name)
+	if err != nil {
+		return fmt.Errorf(util.Msg("cannot start the server: %w"), err)
+	}
+
+	lock, err := inst.lockRunning()
+	if err != nil {
+		return fmt.Errorf(util.Msg("cannot stop the running server: %w"), err)
+	}
+
+	defer lock.Unlock()
 
-	listener, err := getNetListener(o)
+	token, err := newSessionToken()
 	if err != nil {
-		logrus.Fatalf("Cannot open listener: %v", err)
+		return fmt.Errorf(util.Msg("cannot create the session token: %w"), err)
+	}
+
+	// removed only once stopped, so that the server can still be found
+	// while it lets the requests and the jobs finish
+	defer os.Remove(inst.pidFile())
+	defer os.Remove(inst.tokenFile())
+
+	if err := savePidToFile(inst.pidFile()); err != nil {
+		return err
+	}
+
+	if err := saveTokenToFile(token, inst.tokenFile()); err != nil {
+		return err
+	}
+
+	listener, err := getNetListener(o, inst)
+	if err != nil {
+		return fmt.Errorf(util.Msg("cannot open listener: %w"), err)
 	}
 
 	defer listener.Close()
 	server := &http.Server{
-		Handler: createApiHandler(),
+		Handler: createApiHandler(token),
 	}
 
+	served := make(chan error, 1)
 	go func() {
-		savePidToFile(os.Getpid(), pidFile)
 		logrus.Infof("Starting server at %s", listener.Addr().String())
-		if err := server.Serve(listener); err != nil {
-			logrus.Fatalf("Server error: %v", err)
-		}
+		served <- server.Serve(listener)
 	}()
 
+	stopped := make(chan string, 1)
+	stop := func(reason string) {
+		select {
+		case stopped <- reason:
+		default:
+		}
+	}
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	go func() {
+		stop((<-quit).String())
+	}()
+
+	handlers.SetShutdown(func() {
+		stop("requested")
+	})
 
-	logrus.Info("Shutting down server...")
-	os.Remove(pidFile)
+	go watch(o, stop)
 
-	if err := server.Close(); err != nil {
-		logrus.Fatalf("Server close error: %v", err)
+	select {
+	case reason := <-stopped:
+		logrus.Infof("Shutting down server (%s)...", reason)
+	case err := <-served:
+		return fmt.Errorf(util.Msg("server error: %w"), err)
 	}
 
+	shutdown(server)
 	logrus.Info("Server stopped")
+	return nil
 }
 
-func Stop() {
-	pid, err := getActivePid(pidFile)
-	if err == nil {
+// shutdown stops accepting requests, and waits for the requests being
+// served and the jobs running, until shutdownTimeout. The jobs still
+// running then are cancelled.
+func shutdown(server *http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	closed := make(chan error, 1)
+	go func() {
+		closed <- server.Shutdown(ctx)
+	}()
+
+	jobs.wait(ctx)
+	jobs.cancelAll()
+
+	if err := <-closed; err != nil {
+		logrus.Warnf("Server shutdown error: %v", err)
+		server.Close()
+	}
+}
+
+func Stop(name string) {
+	inst, err := newInstance(name)
+	if err != nil {
+		logrus.Fatalf("Cannot stop the server: %v", err)
+	}
+
+	if pid, ok := inst.runningPid(); ok {
 		util.SendSigTermOrKill(pid)
 	}
 }
 
-func createApiHandler() *gin.Engine {
+// createApiHandler serves the API to the clients giving the token,
+// except for the CORS preflight requests
+func createApiHandler(token string) *gin.Engine {
 	gin.SetMode(gin.ReleaseMode)
 
 	r := gin.Default()
 	r.Use(cors.New(cors.Config{
 		AllowMethods:     []string{"GET", "POST", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Content-Type", "Authorization", "If-Match"},
+		ExposeHeaders:    []string{"Content-Length", "ETag"},
 		MaxAge:           12 * time.Hour,
 		AllowCredentials: true,
 		AllowOriginFunc: func(origin string) bool {
@@ -102,7 +193,11 @@ func createApiHandler() *gin.Engine {
 		},
 	}))
 
-	v1 := r.Group("/v1")
+	r.Use(handlers.RequireToken(token))
+	r.Use(serverActivity.track)
+
+	// the routes are documented in openapi.go
+	v1 := r.Group("/v1", handlers.RefreshPresets)
 
 	// read presets or details of the specific preset
 	v1.GET("/presets", handlers.GetPresetsByNameOrType)
@@ -113,45 +208,78 @@ func createApiHandler() *gin.Engine {
 	v1.PATCH("/presets/:id", handlers.PatchCustomPresetById)
 	v1.DELETE("/presets/:id", handlers.DeleteCustomPresetById)
 
+	// share custom presets
+	v1.POST("/presets/export", handlers.PostPresetsExport)
+	v1.POST("/presets/import", handlers.PostPresetsImport)
+
 	// create item (project or file) & validation
 	v1.POST("/items", handlers.PostItems)
 	v1.POST("/items/validate", handlers.PostItemsValidate)
 
+	// create item in the background, following its progress
+	v1.POST("/jobs", postJob)
+	v1.GET("/jobs/:id/events", getJobEvents)
+	v1.DELETE("/jobs/:id", deleteJob)
+
 	// others
 	v1.GET("/ready", handlers.GetReady)
 	v1.DELETE("/server", handlers.DeleteServer)
+	v1.GET("/openapi.json", getOpenApi)
 
 	return r
 }
 
-func ensurePrevRunStopped(filePath string) {
-	pid, err := getActivePid(filePath)
-	if err == nil {
-		util.SendSigTermOrKill(pid)
-		time.Sleep(1 * time.Second)
-	}
-}
-
-func savePidToFile(pid int, filePath string) {
+func savePidToFile(filePath string) error {
 	dir, _ := filepath.Split(filePath)
 	err := os.MkdirAll(dir, 0755)
 	if err != nil {
-		logrus.Fatalf("failed to create directory: %v\n", err)
+		return fmt.Errorf(util.Msg("failed to create directory: %w"), err)
 	}
 
-	err = os.WriteFile(filePath, []byte(fmt.Sprintf("%d", pid)), 0644)
+	err = os.WriteFile(filePath, []byte(pidFileContents()), 0644)
 	if err != nil {
-		logrus.Fatalf("failed to write pid file: %v", err)
+		return fmt.Errorf(util.Msg("failed to write pid file: %w"), err)
 	}
 
 	logrus.Info("pid file created at:", filePath)
+	return nil
+}
+
+// newSessionToken returns a random token, which clients read from the
+// token file to prove that they run as the user who started the server
+func newSessionToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(raw), nil
 }
 
-func getActivePid(filePath string) (int, error) {
-	data, err := os.ReadFile(filePath)
+// saveTokenToFile writes the token where only the user can read it. The
+// file is replaced at once, so that it is never seen empty or half written.
+func saveTokenToFile(token string, filePath string) error {
+	dir, name := filepath.Split(filePath)
+	tmp, err := os.CreateTemp(dir, name+".*.tmp")
+	if err != nil {
+		return fmt.Errorf(util.Msg("failed to write token file: %w"), err)
+	}
+
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.WriteString(token)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(tmp.Name(), filePath)
+	}
+
 	if err != nil {
-		return 0, err
+		return fmt.Errorf(util.Msg("failed to write token file: %w"), err)
 	}
 
-	return strconv.Atoi(string(data))
+	logrus.Info("token file created at:", filePath)
+	return nil
 }
@@ -8,25 +8,10 @@ package server
 import (
 	"net"
 	"os"
-	"path/filepath"
 )
 
-const TempDir = "/tmp/qtcli"
-
-func getPidFilePath() string {
-	if err := os.MkdirAll(TempDir, 0755); err != nil {
-		return ""
-	}
-
-	return filepath.Join(TempDir, "qtcli-server.pid")
-}
-
-func getLocalIpcListener() (net.Listener, error) {
-	if err := os.MkdirAll(TempDir, 0755); err != nil {
-		return nil, err
-	}
-
-	fullPath := filepath.Join(TempDir, "qtcli-server.sock")
+func getLocalIpcListener(i instance) (net.Listener, error) {
+	fullPath := i.filePath(".sock")
 	_, err := os.Stat(fullPath)
 	if !os.IsNotExist(err) {
 		err := os.Remove(fullPath)
@@ -12,16 +12,10 @@ import (
 	"github.com/Microsoft/go-winio"
 )
 
-func getPidFilePath() string {
-	dir := os.Getenv("LOCALAPPDATA") + `\qtcli`
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return ""
-	}
-
-	return dir + `\qtcli-server.pid`
-}
-
-func getLocalIpcListener() (net.Listener, error) {
-	pipeName := `\\.\pipe\qtcli\qtcli-server.pipe`
+// getLocalIpcListener listens on a pipe named after the user, since
+// pipe names are shared by all the users of the machine
+func getLocalIpcListener(i instance) (net.Listener, error) {
+	pipeName := `\\.\pipe\qtcli-` + os.Getenv("USERNAME") + `\` +
+		i.baseName() + ".pipe"
 	return winio.ListenPipe(pipeName, nil)
 }
@@ -0,0 +1,262 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CMakeCommand is a command invocation of a CMake file, e.g.
+// 'qt_add_executable(app main.cpp)'. Offsets are byte offsets in the
+// parsed text, so that the text can be edited without reformatting it.
+type CMakeCommand struct {
+	Name  string
+	Start int // offset of the name
+	Open  int // offset of '('
+	Close int // offset of the matching ')'
+	Args  []CMakeArg
+}
+
+type CMakeArg struct {
+	Value  string // as written, with quotes if any
+	Start  int
+	End    int
+	Quoted bool
+}
+
+// Unquoted returns the value of an argument without the surrounding quotes
+func (a CMakeArg) Unquoted() string {
+	if a.Quoted {
+		return a.Value[1 : len(a.Value)-1]
+	}
+
+	return a.Value
+}
+
+// ParseCMake returns the commands of a CMake file. Nested parentheses in
+// arguments are kept as arguments of their own.
+func ParseCMake(text string) ([]CMakeCommand, error) {
+	p := cmakeParser{text: text}
+	return p.parse()
+}
+
+type cmakeParser struct {
+	text string
+	pos  int
+}
+
+func (p *cmakeParser) parse() ([]CMakeCommand, error) {
+	all := []CMakeCommand{}
+
+	for {
+		p.skipBlanksAndComments()
+		if p.pos >= len(p.text) {
+			return all, nil
+		}
+
+		start := p.pos
+		for p.pos < len(p.text) && isCMakeIdentChar(p.text[p.pos]) {
+			p.pos++
+		}
+
+		if start == p.pos {
+			return nil, p.errorf(Msg("unexpected character '%c'"),
+				p.text[p.pos])
+		}
+
+		cmd := CMakeCommand{Name: p.text[start:p.pos], Start: start}
+
+		for p.pos < len(p.text) && isCMakeBlank(p.text[p.pos]) {
+			p.pos++
+		}
+
+		if p.pos >= len(p.text) || p.text[p.pos] != '(' {
+			return nil, p.errorf(Msg("'(' expected after '%s'"), cmd.Name)
+		}
+
+		cmd.Open = p.pos
+		p.pos++
+
+		if err := p.parseArgs(&cmd); err != nil {
+			return nil, err
+		}
+
+		all = append(all, cmd)
+	}
+}
+
+func (p *cmakeParser) parseArgs(cmd *CMakeCommand) error {
+	depth := 0
+
+	for {
+		p.skipBlanksAndComments()
+		if p.pos >= len(p.text) {
+			return p.errorf(Msg("')' expected to close '%s'"), cmd.Name)
+		}
+
+		start := p.pos
+
+		switch c := p.text[p.pos]; {
+		case c == '(':
+			depth++
+			p.pos++
+			continue
+
+		case c == ')':
+			p.pos++
+			if depth == 0 {
+				cmd.Close = start
+				return nil
+			}
+
+			depth--
+			continue
+
+		case c == '"':
+			if err := p.skipQuoted(); err != nil {
+				return err
+			}
+
+			cmd.Args = append(cmd.Args, CMakeArg{
+				Value:  p.text[start:p.pos],
+				Start:  start,
+				End:    p.pos,
+				Quoted: true,
+			})
+
+		case c == '[' && p.bracketLevel() >= 0:
+			if err := p.skipBracket(); err != nil {
+				return err
+			}
+
+			cmd.Args = append(cmd.Args, CMakeArg{
+				Value: p.text[start:p.pos],
+				Start: start,
+				End:   p.pos,
+			})
+
+		default:
+			p.skipUnquoted()
+			cmd.Args = append(cmd.Args, CMakeArg{
+				Value: p.text[start:p.pos],
+				Start: start,
+				End:   p.pos,
+			})
+		}
+	}
+}
+
+func (p *cmakeParser) skipBlanksAndComments() {
+	for p.pos < len(p.text) {
+		c := p.text[p.pos]
+
+		switch {
+		case isCMakeBlank(c) || c == '\n' || c == '\r':
+			p.pos++
+
+		case c == '#':
+			p.pos++
+			if p.bracketLevel() >= 0 && p.skipBracket() == nil {
+				continue
+			}
+
+			for p.pos < len(p.text) && p.text[p.pos] != '\n' {
+				p.pos++
+			}
+
+		default:
+			return
+		}
+	}
+}
+
+func (p *cmakeParser) skipQuoted() error {
+	start := p.pos
+	p.pos++
+
+	for p.pos < len(p.text) {
+		switch p.text[p.pos] {
+		case '\\':
+			p.pos += 2
+		case '"':
+			p.pos++
+			return nil
+		default:
+			p.pos++
+		}
+	}
+
+	p.pos = start
+	return p.errorf(Msg("unterminated quoted argument"))
+}
+
+// bracketLevel returns the number of '=' of a bracket opening at the
+// current position, e.g. 1 for '[=[', or -1 if there is none
+func (p *cmakeParser) bracketLevel() int {
+	if p.pos >= len(p.text) || p.text[p.pos] != '[' {
+		return -1
+	}
+
+	level := 0
+	for i := p.pos + 1; i < len(p.text); i++ {
+		switch p.text[i] {
+		case '=':
+			level++
+		case '[':
+			return level
+		default:
+			return -1
+		}
+	}
+
+	return -1
+}
+
+func (p *cmakeParser) skipBracket() error {
+	level := p.bracketLevel()
+	closing := "]" + strings.Repeat("=", level) + "]"
+
+	end := strings.Index(p.text[p.pos+level+2:], closing)
+	if end < 0 {
+		return p.errorf(Msg("unterminated bracket argument"))
+	}
+
+	p.pos += level + 2 + end + len(closing)
+	return nil
+}
+
+func (p *cmakeParser) skipUnquoted() {
+	for p.pos < len(p.text) {
+		c := p.text[p.pos]
+
+		switch {
+		case c == '\\':
+			p.pos += 2
+		case isCMakeBlank(c), c == '\n', c == '\r',
+			c == '(', c == ')', c == '#', c == '"':
+			return
+		default:
+			p.pos++
+		}
+	}
+
+	p.pos = min(p.pos, len(p.text))
+}
+
+func (p *cmakeParser) errorf(format string, args ...any) error {
+	line := strings.Count(p.text[:min(p.pos, len(p.text))], "\n") + 1
+	return fmt.Errorf("line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+func isCMakeBlank(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+func isCMakeIdentChar(c byte) bool {
+	return c == '_' ||
+		('a' <= c && c <= 'z') ||
+		('A' <= c && c <= 'Z') ||
+		('0' <= c && c <= '9')
+}
@@ -0,0 +1,67 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCMake(t *testing.T) {
+	text := `# comment (with parens
+cmake_minimum_required(VERSION 3.16)
+#[[ bracket
+comment ]]
+qt_add_executable(app
+    main.cpp # trailing comment
+    "with space.cpp"
+    [=[bracket.cpp]=]
+    $<$<CONFIG:Debug>:debug.cpp>
+)
+if((A AND B) OR C)
+endif()
+`
+
+	cmds, err := ParseCMake(text)
+	require.NoError(t, err)
+
+	names := []string{}
+	for _, cmd := range cmds {
+		names = append(names, cmd.Name)
+	}
+
+	assert.Equal(t, []string{
+		"cmake_minimum_required", "qt_add_executable", "if", "endif"}, names)
+
+	values := []string{}
+	for _, arg := range cmds[1].Args {
+		values = append(values, arg.Unquoted())
+		assert.Equal(t, arg.Value, text[arg.Start:arg.End])
+	}
+
+	assert.Equal(t, []string{"app", "main.cpp", "with space.cpp",
+		"[=[bracket.cpp]=]", "$<$<CONFIG:Debug>:debug.cpp>"}, values)
+	assert.Equal(t, byte(')'), text[cmds[1].Close])
+	assert.Len(t, cmds[2].Args, 5)
+	assert.Empty(t, cmds[3].Args)
+}
+
+func TestParseCMake_Errors(t *testing.T) {
+	all := []string{
+		"qt_add_executable(app\n",
+		"set(x \"unterminated)\n",
+		"project\n",
+		"set(x [[unterminated)\n",
+		"(oops)",
+	}
+
+	for _, text := range all {
+		t.Run(text, func(t *testing.T) {
+			_, err := ParseCMake(text)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package util
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const diffContextLines = 3
+
+type diffOpKind int
+
+const (
+	diffEqual diffOpKind = iota
+	diffDelete
+	diffInsert
+)
+
+type diffOp struct {
+	kind diffOpKind
+	a    int // line index in a, valid for equal and delete
+	b    int // line index in b, valid for equal and insert
+}
+
+// UnifiedDiff returns the differences between two texts in the unified
+// format, with three lines of context. It returns an empty string if the
+// texts are equal.
+func UnifiedDiff(oldText, newText, oldName, newName string) string {
+	a := SplitLinesKeepEnds(oldText)
+	b := SplitLinesKeepEnds(newText)
+	ops := diffLines(a, b)
+
+	if !slices.ContainsFunc(ops, func(op diffOp) bool {
+		return op.kind != diffEqual
+	}) {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+
+	for _, h := range groupHunks(ops) {
+		writeHunk(&sb, ops[h[0]:h[1]], a, b)
+	}
+
+	return sb.String()
+}
+
+// SplitLinesKeepEnds splits the text into lines, keeping the line endings
+func SplitLinesKeepEnds(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if len(lines) != 0 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// diffLines finds the shortest edit script with the Myers algorithm
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	max := n + m
+	offset := max + 1
+	v := make([]int, 2*max+3)
+	trace := [][]int{}
+
+found:
+	for d := 0; d <= max; d++ {
+		trace = append(trace, slices.Clone(v))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break found
+			}
+		}
+	}
+
+	ops := []diffOp{}
+	x, y := n, m
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			ops = append(ops, diffOp{kind: diffEqual, a: x, b: y})
+		}
+
+		if d > 0 {
+			if x == prevX {
+				ops = append(ops, diffOp{kind: diffInsert, a: x, b: prevY})
+			} else {
+				ops = append(ops, diffOp{kind: diffDelete, a: prevX, b: y})
+			}
+		}
+
+		x, y = prevX, prevY
+	}
+
+	slices.Reverse(ops)
+	return ops
+}
+
+// groupHunks returns the [start, end) ranges of ops forming each hunk
+func groupHunks(ops []diffOp) [][2]int {
+	hunks := [][2]int{}
+
+	for i := 0; i < len(ops); i++ {
+		if ops[i].kind == diffEqual {
+			continue
+		}
+
+		start := max(0, i-diffContextLines)
+		if len(hunks) != 0 && start <= hunks[len(hunks)-1][1] {
+			start = hunks[len(hunks)-1][0]
+			hunks = hunks[:len(hunks)-1]
+		}
+
+		end := i
+		for end < len(ops) && ops[end].kind != diffEqual {
+			end++
+		}
+
+		i = end - 1
+		hunks = append(hunks, [2]int{
+			start, min(len(ops), end+diffContextLines)})
+	}
+
+	return hunks
+}
+
+func writeHunk(sb *strings.Builder, ops []diffOp, a, b []string) {
+	aStart, bStart := -1, -1
+	aLen, bLen := 0, 0
+
+	for _, op := range ops {
+		if op.kind != diffInsert {
+			if aStart < 0 {
+				aStart = op.a
+			}
+			aLen++
+		}
+
+		if op.kind != diffDelete {
+			if bStart < 0 {
+				bStart = op.b
+			}
+			bLen++
+		}
+	}
+
+	if aStart < 0 {
+		aStart = ops[0].a
+	}
+
+	if bStart < 0 {
+		bStart = ops[0].b
+	}
+
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n",
+		hunkRange(aStart, aLen), hunkRange(bStart, bLen))
+
+	for _, op := range ops {
+		switch op.kind {
+		case diffEqual:
+			writeDiffLine(sb, " ", a[op.a])
+		case diffDelete:
+			writeDiffLine(sb, "-", a[op.a])
+		case diffInsert:
+			writeDiffLine(sb, "+", b[op.b])
+		}
+	}
+}
+
+func hunkRange(start, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+
+	if length == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, length)
+}
+
+func writeDiffLine(sb *strings.Builder, prefix, line string) {
+	sb.WriteString(prefix)
+	sb.WriteString(line)
+
+	if !strings.HasSuffix(line, "\n") {
+		sb.WriteString("\n\\ No newline at end of file\n")
+	}
+}
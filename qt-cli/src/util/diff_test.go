@@ -0,0 +1,55 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package util
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	type testCase struct {
+		oldText  string
+		newText  string
+		expected string
+	}
+
+	lines := func(from, to int) string {
+		all := []string{}
+		for i := from; i <= to; i++ {
+			all = append(all, fmt.Sprintf("%d\n", i))
+		}
+
+		return strings.Join(all, "")
+	}
+
+	all := []testCase{
+		{"a\nb\n", "a\nb\n", ""},
+		{"", "", ""},
+		{"", "a\nb\n", "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n"},
+		{"a\nb\n", "", "--- old\n+++ new\n@@ -1,2 +0,0 @@\n-a\n-b\n"},
+		{"a\nb\nc\n", "a\nx\nc\n",
+			"--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n"},
+		{"a\n", "a",
+			"--- old\n+++ new\n@@ -1 +1 @@\n-a\n+a\n\\ No newline at end of file\n"},
+		{lines(1, 10), strings.Replace(lines(1, 10), "5\n", "five\n", 1),
+			"--- old\n+++ new\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n"},
+		{lines(1, 20), strings.Replace(strings.Replace(
+			lines(1, 20), "2\n", "", 1), "19\n", "", 1),
+			"--- old\n+++ new\n" +
+				"@@ -1,5 +1,4 @@\n 1\n-2\n 3\n 4\n 5\n" +
+				"@@ -16,5 +15,4 @@\n 16\n 17\n 18\n-19\n 20\n"},
+	}
+
+	for _, tc := range all {
+		name := fmt.Sprintf("|%q|%q|", tc.oldText, tc.newText)
+		t.Run(name, func(t *testing.T) {
+			actual := UnifiedDiff(tc.oldText, tc.newText, "old", "new")
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
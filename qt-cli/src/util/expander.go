@@ -5,22 +5,70 @@ package util
 
 import (
 	"bytes"
+	"errors"
 	"io"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
 	"strings"
 	"text/template"
 )
 
 type TemplateExpander struct {
-	data  StringAnyMap
-	funcs template.FuncMap
-	name  string
+	data     StringAnyMap
+	funcs    template.FuncMap
+	name     string
+	partials map[string]string
 }
 
+// defaultPartials are available to every expander, see UseDefaultPartials
+var defaultPartials = map[string]string{}
+
 func NewTemplateExpander() *TemplateExpander {
 	return &TemplateExpander{
-		data:  StringAnyMap{},
-		funcs: template.FuncMap{},
+		data:     StringAnyMap{},
+		funcs:    template.FuncMap{},
+		partials: maps.Clone(defaultPartials),
+	}
+}
+
+// UseDefaultPartials sets the named templates that every expander
+// created afterwards can use with {{ template "name" . }}
+func UseDefaultPartials(partials map[string]string) {
+	defaultPartials = maps.Clone(partials)
+}
+
+// LoadPartials reads every file of the given directory as a partial
+// named after the file, without its extension. A missing directory
+// gives no partials.
+func LoadPartials(fsys fs.FS, dir string) (map[string]string, error) {
+	all := map[string]string{}
+
+	entries, err := fs.ReadDir(fsys, dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return all, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		raw, err := ReadAllFromFS(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		name := strings.TrimSuffix(entry.Name(), path.Ext(entry.Name()))
+		all[name] = string(raw)
 	}
+
+	return all, nil
 }
 
 func (e *TemplateExpander) Name(name string) *TemplateExpander {
@@ -43,11 +91,19 @@ func (e *TemplateExpander) Funcs(funcs template.FuncMap) *TemplateExpander {
 	return e
 }
 
+// Partials adds named templates, overriding the ones of the same name
+func (e *TemplateExpander) Partials(partials map[string]string) *TemplateExpander {
+	maps.Copy(e.partials, partials)
+	return e
+}
+
 func (e *TemplateExpander) RunString(templateString string) (string, error) {
-	return e.execTemplate(template.
-		New(e.name).
-		Funcs(e.funcs).
-		Parse(templateString))
+	tmpl, err := e.newTemplate()
+	if err != nil {
+		return "", err
+	}
+
+	return e.execTemplate(tmpl.Parse(templateString))
 }
 
 func (e *TemplateExpander) RunStringToBool(
@@ -66,10 +122,24 @@ func (e *TemplateExpander) RunStringToBool(
 }
 
 func (e *TemplateExpander) RunFile(filePath string) (string, error) {
-	return e.execTemplate(template.
-		New(e.name).
-		Funcs(e.funcs).
-		ParseFiles(filePath))
+	tmpl, err := e.newTemplate()
+	if err != nil {
+		return "", err
+	}
+
+	return e.execTemplate(tmpl.ParseFiles(filePath))
+}
+
+func (e *TemplateExpander) newTemplate() (*template.Template, error) {
+	tmpl := template.New(e.name).Funcs(e.funcs)
+
+	for _, name := range slices.Sorted(maps.Keys(e.partials)) {
+		if _, err := tmpl.New(name).Parse(e.partials[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	return tmpl, nil
 }
 
 func (e *TemplateExpander) execTemplate(
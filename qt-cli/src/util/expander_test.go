@@ -0,0 +1,45 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package util
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateExpander_Partials(t *testing.T) {
+	fsys := fstest.MapFS{
+		"partials/license-header.tmpl": {Data: []byte("// (c) {{.owner}}")},
+		"partials/greeting.txt":        {Data: []byte("hello")},
+	}
+
+	partials, err := LoadPartials(fsys, "partials")
+	require.NoError(t, err)
+	require.Len(t, partials, 2)
+
+	none, err := LoadPartials(fsys, "missing")
+	require.NoError(t, err)
+	require.Empty(t, none)
+
+	saved := defaultPartials
+	defer UseDefaultPartials(saved)
+	UseDefaultPartials(partials)
+
+	out, err := NewTemplateExpander().
+		Data(StringAnyMap{"owner": "me"}).
+		RunString(`{{template "license-header" .}} {{template "greeting"}}`)
+	require.NoError(t, err)
+	require.Equal(t, "// (c) me hello", out)
+
+	out, err = NewTemplateExpander().
+		Partials(map[string]string{"greeting": "hi"}).
+		RunString(`{{template "greeting"}}`)
+	require.NoError(t, err)
+	require.Equal(t, "hi", out)
+
+	_, err = NewTemplateExpander().RunString(`{{template "unknown"}}`)
+	require.Error(t, err)
+}
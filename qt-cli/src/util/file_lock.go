@@ -0,0 +1,152 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+const (
+	FileLockTimeout  = 10 * time.Second
+	fileLockInterval = 20 * time.Millisecond
+)
+
+// FileLock is an exclusive lock shared by the processes of the user.
+// It is held on a file of the runtime directory named after the path of
+// the locked file, because the locked file itself may be replaced while
+// the lock is held, and nothing should be left next to it.
+type FileLock struct {
+	file *os.File
+}
+
+// ErrFileLocked tells that another process holds the lock
+var ErrFileLocked = errors.New(Msg("file is locked by another process"))
+
+// LockFile locks the given file, waiting for other processes to release
+// it for at most FileLockTimeout
+func LockFile(filePath string) (*FileLock, error) {
+	return LockFileWithin(filePath, FileLockTimeout)
+}
+
+// TryLockFile locks the given file, or fails with ErrFileLocked at once
+// if another process holds the lock
+func TryLockFile(filePath string) (*FileLock, error) {
+	return LockFileWithin(filePath, 0)
+}
+
+// LockFileWithin is like LockFile, waiting for the given time at most
+func LockFileWithin(filePath string, timeout time.Duration) (*FileLock, error) {
+	lockPath, err := lockFilePath(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		locked, err := tryLockFile(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+
+		if locked {
+			return &FileLock{file: f}, nil
+		}
+
+		if !time.Now().Before(deadline) {
+			f.Close()
+			return nil, fmt.Errorf("%w, given = '%v'", ErrFileLocked, filePath)
+		}
+
+		time.Sleep(fileLockInterval)
+	}
+}
+
+// lockFilePath returns the lock file of the given file, in the 'locks'
+// directory of the runtime directory, like 'presets.yml-<hash>.lock'
+// where the hash is the one of the absolute path of the file
+func lockFilePath(filePath string) (string, error) {
+	abs, err := filepath.Abs(filepath.FromSlash(filePath))
+	if err != nil {
+		return "", err
+	}
+
+	if dir, err := filepath.EvalSymlinks(filepath.Dir(abs)); err == nil {
+		abs = filepath.Join(dir, filepath.Base(abs))
+	}
+
+	runtimeDir, err := RuntimeDir()
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(runtimeDir, "locks")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(abs))
+	name := fmt.Sprintf("%s-%s.lock",
+		filepath.Base(abs), hex.EncodeToString(sum[:8]))
+
+	return filepath.Join(dir, name), nil
+}
+
+func (l *FileLock) Unlock() error {
+	defer l.file.Close()
+	return unlockFile(l.file)
+}
+
+// WriteAllAtomic is like WriteAll, but replaces the file at once through
+// a temporary file, so that readers never see a partly written file
+func WriteAllAtomic(data []byte, destPath string) error {
+	dir := path.Dir(destPath)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(destPath); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(dir, path.Base(destPath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), destPath)
+}
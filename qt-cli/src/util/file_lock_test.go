@@ -0,0 +1,99 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package util
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteAllAtomic(t *testing.T) {
+	dir := filepath.ToSlash(t.TempDir())
+	file := path.Join(dir, "sub", "file.yml")
+
+	require.NoError(t, WriteAllAtomic([]byte("first, and longer"), file))
+	require.NoError(t, os.Chmod(file, 0600))
+	require.NoError(t, WriteAllAtomic([]byte("second"), file))
+
+	raw, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(raw))
+
+	entries, err := os.ReadDir(path.Join(dir, "sub"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	if info, err := os.Stat(file); assert.NoError(t, err) {
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm()&0600)
+	}
+}
+
+func TestLockFile(t *testing.T) {
+	useTempRuntimeDir(t)
+	dir := filepath.ToSlash(t.TempDir())
+	file := path.Join(dir, "file.yml")
+
+	first, err := LockFile(file)
+	require.NoError(t, err)
+
+	_, err = TryLockFile(file)
+	require.ErrorIs(t, err, ErrFileLocked)
+
+	locked := make(chan struct{})
+	go func() {
+		second, err := LockFile(file)
+		if assert.NoError(t, err) {
+			second.Unlock()
+		}
+
+		close(locked)
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("locked twice")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	require.NoError(t, first.Unlock())
+
+	select {
+	case <-locked:
+	case <-time.After(FileLockTimeout):
+		t.Fatal("not locked after the release")
+	}
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestLockFile_Paths(t *testing.T) {
+	useTempRuntimeDir(t)
+	dir := filepath.ToSlash(t.TempDir())
+
+	first, err := LockFile(path.Join(dir, "a", "presets.yml"))
+	require.NoError(t, err)
+	defer first.Unlock()
+
+	second, err := TryLockFile(path.Join(dir, "b", "presets.yml"))
+	require.NoError(t, err)
+	defer second.Unlock()
+
+	_, err = TryLockFile(path.Join(dir, "a", "..", "a", "presets.yml"))
+	require.ErrorIs(t, err, ErrFileLocked)
+}
+
+// useTempRuntimeDir keeps the lock files of the test in a directory
+// of its own
+func useTempRuntimeDir(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	t.Setenv("LOCALAPPDATA", t.TempDir())
+}
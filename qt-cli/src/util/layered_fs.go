@@ -0,0 +1,125 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package util
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+)
+
+// LayeredFS stacks several file systems on top of each other.
+// Layers are searched in the given order, so a path that exists in
+// more than one layer is always resolved by the first one.
+//
+// When a marker file name is set, directories containing that file are
+// treated as a unit: the first layer that has the marker owns the whole
+// directory, and nothing below it is merged from lower layers.
+type LayeredFS struct {
+	layers []fs.FS
+	marker string
+}
+
+func NewLayeredFS(layers ...fs.FS) *LayeredFS {
+	return &LayeredFS{layers: layers}
+}
+
+func (l *LayeredFS) MarkerFile(name string) *LayeredFS {
+	l.marker = name
+	return l
+}
+
+func (l *LayeredFS) GetLayers() []fs.FS {
+	return l.layers
+}
+
+func (l *LayeredFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	for _, layer := range l.candidates(name) {
+		f, err := layer.Open(name)
+		if err == nil {
+			return f, nil
+		}
+	}
+
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+func (l *LayeredFS) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+
+	for _, layer := range l.candidates(name) {
+		info, err := fs.Stat(layer, name)
+		if err == nil {
+			return info, nil
+		}
+	}
+
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+func (l *LayeredFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+
+	found := false
+	seen := map[string]bool{}
+	all := []fs.DirEntry{}
+
+	for _, layer := range l.candidates(name) {
+		entries, err := fs.ReadDir(layer, name)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		found = true
+		for _, entry := range entries {
+			if !seen[entry.Name()] {
+				seen[entry.Name()] = true
+				all = append(all, entry)
+			}
+		}
+	}
+
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	slices.SortFunc(all, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+
+	return all, nil
+}
+
+// candidates returns the layers allowed to resolve the given path
+func (l *LayeredFS) candidates(name string) []fs.FS {
+	if len(l.marker) == 0 || len(l.layers) < 2 {
+		return l.layers
+	}
+
+	for dir := name; dir != "."; dir = path.Dir(dir) {
+		markerPath := path.Join(dir, l.marker)
+
+		for _, layer := range l.layers {
+			if _, err := fs.Stat(layer, markerPath); err == nil {
+				return []fs.FS{layer}
+			}
+		}
+	}
+
+	return l.layers
+}
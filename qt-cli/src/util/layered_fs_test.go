@@ -0,0 +1,94 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package util
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLayeredFS_FirstLayerWins(t *testing.T) {
+	upper := fstest.MapFS{
+		"common/a.txt": {Data: []byte("upper")},
+	}
+
+	lower := fstest.MapFS{
+		"common/a.txt": {Data: []byte("lower")},
+		"common/b.txt": {Data: []byte("lower")},
+	}
+
+	l := NewLayeredFS(upper, lower)
+
+	data, err := fs.ReadFile(l, "common/a.txt")
+	require.NoError(t, err)
+	require.Equal(t, "upper", string(data))
+
+	data, err = fs.ReadFile(l, "common/b.txt")
+	require.NoError(t, err)
+	require.Equal(t, "lower", string(data))
+
+	entries, err := fs.ReadDir(l, "common")
+	require.NoError(t, err)
+	require.Equal(t, []string{"a.txt", "b.txt"}, entryNames(entries))
+
+	_, err = l.Open("common/none.txt")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestLayeredFS_MarkerOwnsDirectory(t *testing.T) {
+	upper := fstest.MapFS{
+		"projects/app/templates.yml": {Data: []byte("upper")},
+		"projects/app/main.cpp":      {Data: []byte("upper")},
+	}
+
+	lower := fstest.MapFS{
+		"projects/app/templates.yml": {Data: []byte("lower")},
+		"projects/app/main.cpp":      {Data: []byte("lower")},
+		"projects/app/prompt.yml":    {Data: []byte("lower")},
+		"projects/lib/templates.yml": {Data: []byte("lower")},
+	}
+
+	l := NewLayeredFS(upper, lower).MarkerFile("templates.yml")
+
+	require.True(t, EntryExistsFS(l, "projects/app/main.cpp"))
+	require.False(t, EntryExistsFS(l, "projects/app/prompt.yml"))
+	require.True(t, EntryExistsFS(l, "projects/lib/templates.yml"))
+
+	entries, err := fs.ReadDir(l, "projects/app")
+	require.NoError(t, err)
+	require.Equal(t, []string{"main.cpp", "templates.yml"}, entryNames(entries))
+
+	entries, err = fs.ReadDir(l, "projects")
+	require.NoError(t, err)
+	require.Equal(t, []string{"app", "lib"}, entryNames(entries))
+
+	found := []string{}
+	err = fs.WalkDir(l, ".", func(p string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			found = append(found, p)
+		}
+
+		return err
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"projects/app/main.cpp",
+		"projects/app/templates.yml",
+		"projects/lib/templates.yml",
+	}, found)
+}
+
+func entryNames(entries []fs.DirEntry) []string {
+	names := []string{}
+
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+
+	return names
+}
@@ -0,0 +1,108 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package util
+
+import (
+	"slices"
+	"strings"
+)
+
+const (
+	MergeMarkerOurs   = "<<<<<<<"
+	MergeMarkerSep    = "======="
+	MergeMarkerTheirs = ">>>>>>>"
+)
+
+// Merge3 merges the changes made to base in ours and in theirs, line by
+// line. Where both changed the same lines differently, both versions are
+// kept between conflict markers, labelled with the given names, and the
+// number of such conflicts is returned.
+func Merge3(base, ours, theirs, oursName, theirsName string) (string, int) {
+	b := SplitLinesKeepEnds(base)
+	o := SplitLinesKeepEnds(ours)
+	t := SplitLinesKeepEnds(theirs)
+
+	// where each line of base is found in ours and theirs, or -1
+	inOurs := matchLines(b, o)
+	inTheirs := matchLines(b, t)
+
+	out := strings.Builder{}
+	conflicts := 0
+	i, j, k := 0, 0, 0
+
+	for i < len(b) || j < len(o) || k < len(t) {
+		// a line kept by both, right where we are
+		if i < len(b) && inOurs[i] == j && inTheirs[i] == k {
+			out.WriteString(b[i])
+			i, j, k = i+1, j+1, k+1
+			continue
+		}
+
+		// otherwise, the changes up to the next line kept by both
+		next := i
+		for next < len(b) && (inOurs[next] < j || inTheirs[next] < k) {
+			next++
+		}
+
+		nextOurs, nextTheirs := len(o), len(t)
+		if next < len(b) {
+			nextOurs, nextTheirs = inOurs[next], inTheirs[next]
+		}
+
+		baseChunk := b[i:next]
+		oursChunk := o[j:nextOurs]
+		theirsChunk := t[k:nextTheirs]
+
+		switch {
+		case slices.Equal(oursChunk, baseChunk):
+			writeLines(&out, theirsChunk)
+		case slices.Equal(theirsChunk, baseChunk),
+			slices.Equal(oursChunk, theirsChunk):
+			writeLines(&out, oursChunk)
+		default:
+			conflicts++
+			out.WriteString(MergeMarkerOurs + " " + oursName + "\n")
+			writeLinesEnded(&out, oursChunk)
+			out.WriteString(MergeMarkerSep + "\n")
+			writeLinesEnded(&out, theirsChunk)
+			out.WriteString(MergeMarkerTheirs + " " + theirsName + "\n")
+		}
+
+		i, j, k = next, nextOurs, nextTheirs
+	}
+
+	return out.String(), conflicts
+}
+
+// matchLines returns, for every line of a, the index of the same line in
+// b according to their shortest edit script, or -1 if it was removed
+func matchLines(a, b []string) []int {
+	all := make([]int, len(a))
+	for i := range all {
+		all[i] = -1
+	}
+
+	for _, op := range diffLines(a, b) {
+		if op.kind == diffEqual {
+			all[op.a] = op.b
+		}
+	}
+
+	return all
+}
+
+func writeLines(out *strings.Builder, lines []string) {
+	for _, line := range lines {
+		out.WriteString(line)
+	}
+}
+
+// writeLinesEnded writes lines, making sure that the last one is ended,
+// so that a conflict marker can follow
+func writeLinesEnded(out *strings.Builder, lines []string) {
+	writeLines(out, lines)
+	if len(lines) != 0 && !strings.HasSuffix(lines[len(lines)-1], "\n") {
+		out.WriteString("\n")
+	}
+}
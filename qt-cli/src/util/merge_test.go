@@ -0,0 +1,45 @@
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package util
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge3(t *testing.T) {
+	type testCase struct {
+		base      string
+		ours      string
+		theirs    string
+		expected  string
+		conflicts int
+	}
+
+	all := []testCase{
+		{"a\nb\nc\n", "a\nb\nc\n", "a\nb\nc\n", "a\nb\nc\n", 0},
+		{"a\nb\nc\n", "A\nb\nc\n", "a\nb\nc\n", "A\nb\nc\n", 0},
+		{"a\nb\nc\n", "a\nb\nc\n", "a\nb\nC\n", "a\nb\nC\n", 0},
+		{"a\nb\nc\n", "A\nb\nc\n", "a\nb\nC\n", "A\nb\nC\n", 0},
+		{"a\nb\nc\n", "a\nB\nc\n", "a\nB\nc\n", "a\nB\nc\n", 0},
+		{"a\nb\nc\n", "a\nc\n", "a\nb\nc\nd\n", "a\nc\nd\n", 0},
+		{"a\nb\nc\n", "a\nX\nc\n", "a\nY\nc\n",
+			"a\n<<<<<<< ours\nX\n=======\nY\n>>>>>>> theirs\nc\n", 1},
+		{"a\n", "a\nx", "a\ny\n",
+			"a\n<<<<<<< ours\nx\n=======\ny\n>>>>>>> theirs\n", 1},
+		{"", "x\n", "y\n",
+			"<<<<<<< ours\nx\n=======\ny\n>>>>>>> theirs\n", 1},
+	}
+
+	for _, tc := range all {
+		name := fmt.Sprintf("|%q|%q|%q|", tc.base, tc.ours, tc.theirs)
+		t.Run(name, func(t *testing.T) {
+			actual, conflicts := Merge3(tc.base, tc.ours, tc.theirs, "ours", "theirs")
+			assert.Equal(t, tc.expected, actual)
+			assert.Equal(t, tc.conflicts, conflicts)
+		})
+	}
+}
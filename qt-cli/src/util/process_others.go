@@ -0,0 +1,18 @@
+//go:build !windows
+
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package util
+
+import (
+	"errors"
+	"syscall"
+)
+
+// ProcessExists tells if a process with the given pid runs, including
+// one of another user
+func ProcessExists(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
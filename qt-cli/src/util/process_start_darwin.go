@@ -0,0 +1,29 @@
+//go:build darwin
+
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package util
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// ProcessStartTime returns when the process with the given pid started,
+// in nanoseconds. Together with the pid, it tells a process apart from
+// a later one reusing the pid.
+func ProcessStartTime(pid int) (int64, error) {
+	info, err := unix.SysctlKinfoProc("kern.proc.pid", pid)
+	if err != nil {
+		return 0, err
+	}
+
+	// no error is returned for a pid without a process
+	if int(info.Proc.P_pid) != pid {
+		return 0, os.ErrProcessDone
+	}
+
+	return info.Proc.P_starttime.Nano(), nil
+}
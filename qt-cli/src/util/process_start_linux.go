@@ -0,0 +1,35 @@
+//go:build linux
+
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package util
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ProcessStartTime returns when the process with the given pid started,
+// in clock ticks since boot. Together with the pid, it tells a process
+// apart from a later one reusing the pid.
+func ProcessStartTime(pid int) (int64, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, err
+	}
+
+	// the name of the command, in parentheses, may contain spaces;
+	// the fields after it start with the third one of the file
+	end := bytes.LastIndexByte(data, ')')
+	fields := strings.Fields(string(data[end+1:]))
+	if end < 0 || len(fields) < 20 {
+		return 0, errors.New(Msg("invalid process status"))
+	}
+
+	return strconv.ParseInt(fields[19], 10, 64)
+}
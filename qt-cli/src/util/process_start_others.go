@@ -0,0 +1,15 @@
+//go:build !linux && !darwin && !windows
+
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package util
+
+import (
+	"errors"
+)
+
+// ProcessStartTime is not supported on this platform
+func ProcessStartTime(pid int) (int64, error) {
+	return 0, errors.ErrUnsupported
+}
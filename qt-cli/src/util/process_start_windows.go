@@ -0,0 +1,31 @@
+//go:build windows
+
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package util
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// ProcessStartTime returns when the process with the given pid started,
+// in nanoseconds. Together with the pid, it tells a process apart from
+// a later one reusing the pid.
+func ProcessStartTime(pid int) (int64, error) {
+	h, err := windows.OpenProcess(
+		windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return 0, err
+	}
+
+	defer windows.CloseHandle(h)
+
+	var creation, exit, kernel, user windows.Filetime
+	err = windows.GetProcessTimes(h, &creation, &exit, &kernel, &user)
+	if err != nil {
+		return 0, err
+	}
+
+	return creation.Nanoseconds(), nil
+}
@@ -0,0 +1,48 @@
+//go:build !windows
+
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package util
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// RuntimeDir returns the directory of the runtime files of the user,
+// like sockets and locks: '$XDG_RUNTIME_DIR/qtcli' or, without it,
+// a directory named after the user id in the temporary directory
+func RuntimeDir() (string, error) {
+	dir := filepath.Join(os.TempDir(), fmt.Sprintf("qtcli-%d", os.Getuid()))
+	if xdg := os.Getenv("XDG_RUNTIME_DIR"); len(xdg) != 0 {
+		dir = filepath.Join(xdg, "qtcli")
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	// an existing directory must be the user's own, and not a link which
+	// could lead anywhere; it is then restricted to the user
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return "", err
+	}
+
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !info.IsDir() || (ok && int(stat.Uid) != os.Getuid()) {
+		return "", fmt.Errorf(
+			Msg("not a directory of the current user, given = '%v'"), dir)
+	}
+
+	if info.Mode().Perm()&0077 != 0 {
+		if err := os.Chmod(dir, 0700); err != nil {
+			return "", err
+		}
+	}
+
+	return dir, nil
+}
@@ -0,0 +1,28 @@
+//go:build windows
+
+// Copyright (C) 2025 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package util
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// RuntimeDir returns the directory of the runtime files of the user,
+// like locks: '%LOCALAPPDATA%\qtcli'. It fails without LOCALAPPDATA,
+// rather than using the root of the current drive.
+func RuntimeDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(cacheDir, "qtcli")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	return dir, nil
+}
@@ -63,6 +63,17 @@ func WriteAll(data []byte, destPath string) (int, error) {
 	return destFile.Write(data)
 }
 
+// NextBackupPath returns 'file.bak', or 'file.bak.N' if that is taken
+func NextBackupPath(filePath string) string {
+	candidate := filePath + ".bak"
+
+	for i := 1; EntryExists(candidate); i++ {
+		candidate = fmt.Sprintf("%s.bak.%d", filePath, i)
+	}
+
+	return candidate
+}
+
 func EntryExists(path string) bool {
 	_, err := os.Stat(path)
 	return !os.IsNotExist(err)
@@ -214,3 +225,67 @@ func NormalizeFileExt(fileName, fallbackExt string) string {
 
 	return effectiveName + fallbackExt
 }
+
+// SplitCommandLine splits a command line into arguments, the way a
+// POSIX shell does for plain words and quotes, but without expanding
+// anything. A backslash only escapes a quote, a blank or a backslash,
+// so that Windows paths are kept as they are.
+func SplitCommandLine(line string) ([]string, error) {
+	args := []string{}
+	var current strings.Builder
+	inWord := false
+	quote := rune(0)
+	runes := []rune(line)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+
+		case r == '\\' && i+1 < len(runes) &&
+			strings.ContainsRune(`"'\ `+"\t", runes[i+1]) &&
+			(quote == 0 || runes[i+1] != '\''):
+			i++
+			current.WriteRune(runes[i])
+			inWord = true
+
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inWord {
+				args = append(args, current.String())
+				current.Reset()
+				inWord = false
+			}
+
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf(Msg("unterminated quote in '%v'"), line)
+	}
+
+	if inWord {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
@@ -4,9 +4,13 @@
 package util
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"os/exec"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -58,3 +62,60 @@ func TestNormalizeFileExt(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitCommandLine(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected []string
+		hasError bool
+	}{
+		{"", []string{}, false},
+		{"  git   init ", []string{"git", "init"}, false},
+		{`cmake -S . -B "my build"`, []string{"cmake", "-S", ".", "-B", "my build"}, false},
+		{`echo 'a "b" c'`, []string{"echo", `a "b" c`}, false},
+		{`echo "a \"b\" c"`, []string{"echo", `a "b" c`}, false},
+		{`echo a\ b`, []string{"echo", "a b"}, false},
+		{`echo ''`, []string{"echo", ""}, false},
+		{`C:\tools\x.exe C:\dir`, []string{`C:\tools\x.exe`, `C:\dir`}, false},
+		{`echo "unterminated`, nil, true},
+	}
+
+	for _, tc := range tests {
+		testname := fmt.Sprintf("|%s|", tc.line)
+		t.Run(testname, func(t *testing.T) {
+			actual, err := SplitCommandLine(tc.line)
+			if tc.hasError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestProcessExists(t *testing.T) {
+	require.True(t, ProcessExists(os.Getpid()))
+
+	cmd := exec.Command("go", "version")
+	require.NoError(t, cmd.Run())
+	require.False(t, ProcessExists(cmd.Process.Pid))
+}
+
+func TestProcessStartTime(t *testing.T) {
+	first, err := ProcessStartTime(os.Getpid())
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip(err)
+	}
+
+	require.NoError(t, err)
+	second, err := ProcessStartTime(os.Getpid())
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	cmd := exec.Command("go", "version")
+	require.NoError(t, cmd.Run())
+	_, err = ProcessStartTime(cmd.Process.Pid)
+	require.Error(t, err)
+}
@@ -1,11 +1,33 @@
 // Copyright (C) 2025 The Qt Company Ltd.
 // SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
 
+import * as fs from 'fs';
 import os from 'os';
+import * as path from 'path';
 import axios, { AxiosRequestConfig, isAxiosError } from 'axios';
 
 import { isErrorResponse, Issue } from '@/webview/shared/message';
 
+// the files of the server are in a directory of the user,
+// the same as the one of 'qtcli server start'
+function runtimeDir() {
+  if (os.platform() === 'win32') {
+    return path.join(process.env.LOCALAPPDATA ?? '', 'qtcli');
+  }
+
+  const xdg = process.env.XDG_RUNTIME_DIR;
+  return xdg
+    ? path.join(xdg, 'qtcli')
+    : path.join(os.tmpdir(), `qtcli-${os.userInfo().uid}`);
+}
+
+// each extension host runs its own server, so that starting one does not
+// stop the server of another window
+export const qtcliServerName = `vscode-${process.pid}`;
+
+const serverBaseName = `qtcli-server-${qtcliServerName}`;
+const tokenFilePath = path.join(runtimeDir(), `${serverBaseName}.token`);
+
 // axios wrapper
 export class QtcliRestClient {
   readonly _api = axios.create({
@@ -13,13 +35,26 @@ export class QtcliRestClient {
     timeout: 15 * 1000,
     socketPath:
       os.platform() !== 'win32'
-        ? '/tmp/qtcli/qtcli-server.sock'
-        : String.raw`\\.\pipe\qtcli\qtcli-server.pipe`
+        ? path.join(runtimeDir(), `${serverBaseName}.sock`)
+        : String.raw`\\.\pipe\qtcli-` +
+          `${process.env.USERNAME ?? ''}\\${serverBaseName}.pipe`
   });
 
   private readonly _maxRetries = 75;
   private readonly _retryDelay = 200;
 
+  constructor() {
+    // read on every request, since the token changes when the server restarts
+    this._api.interceptors.request.use((config) => {
+      const token = readSessionToken();
+      if (token) {
+        config.headers.Authorization = `Bearer ${token}`;
+      }
+
+      return config;
+    });
+  }
+
   // convenients
   public async get(url: string, params?: unknown) {
     return this.call({ method: 'get', url, params });
@@ -115,6 +150,14 @@ export class QtcliRestError extends Error {
   }
 }
 
+function readSessionToken(): string | undefined {
+  try {
+    return fs.readFileSync(tokenFilePath, 'utf8').trim();
+  } catch {
+    return undefined;
+  }
+}
+
 function makeV1Prefix(req: AxiosRequestConfig): AxiosRequestConfig {
   const raw = req.url?.trim();
   const url =
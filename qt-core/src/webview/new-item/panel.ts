@@ -7,6 +7,7 @@ import { createLogger } from 'qt-lib';
 import { QtcliRunner } from '@/qtcli/runner';
 import { QtcliAction } from '@/qtcli/common';
 import { findQtcliExePath } from '@/qtcli/commands';
+import { qtcliServerName } from '@/qtcli/rest';
 import { WebviewChannel } from '@/webview/channel';
 import { NewItemDispatcher } from './dispatcher';
 import * as texts from '@/texts';
@@ -105,5 +106,9 @@ async function startQtcliServer(extensionUri: vscode.Uri) {
     }
   }
 
-  qtcliRunner.run(QtcliAction.ServerControl, 'start');
+  // the server stops with the extension host, even if it crashes
+  qtcliRunner.run(
+    QtcliAction.ServerControl,
+    `start --name ${qtcliServerName} --parent-pid ${process.pid}`
+  );
 }